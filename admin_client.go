@@ -0,0 +1,349 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	xxl "github.com/xxl-job/xxl-job-executor-go"
+)
+
+const (
+	// defaultAdminTimeout 调度中心接口默认请求超时
+	defaultAdminTimeout = 10 * time.Second
+	// maxAdminResponseSize 调度中心接口响应体最大大小
+	maxAdminResponseSize = 10 * 1024 * 1024
+)
+
+// errAdminUnauthorized 未登录或登录已过期（调度中心重定向到登录页）
+var errAdminUnauthorized = errors.New("xxl-job admin: not logged in")
+
+// AdminConfig 调度中心管理接口配置（用于 AdminClient 和命令行工具，执行器本身不需要）
+type AdminConfig struct {
+	Username string        `yaml:"username" env:"XXL_JOB_ADMIN_USERNAME"`
+	Password string        `yaml:"password" env:"XXL_JOB_ADMIN_PASSWORD"`
+	Timeout  time.Duration `yaml:"timeout" env:"XXL_JOB_ADMIN_TIMEOUT" default:"10s"`
+}
+
+// AdminJob 调度中心任务信息
+type AdminJob struct {
+	ID                     int64  `json:"id"`
+	JobGroup               int64  `json:"jobGroup"`
+	JobDesc                string `json:"jobDesc"`
+	Author                 string `json:"author"`
+	ScheduleType           string `json:"scheduleType"`
+	ScheduleConf           string `json:"scheduleConf"`
+	ExecutorRouteStrategy  string `json:"executorRouteStrategy"`
+	ExecutorHandler        string `json:"executorHandler"`
+	ExecutorParam          string `json:"executorParam"`
+	ExecutorBlockStrategy  string `json:"executorBlockStrategy"`
+	ExecutorTimeout        int    `json:"executorTimeout"`
+	ExecutorFailRetryCount int    `json:"executorFailRetryCount"`
+	TriggerStatus          int    `json:"triggerStatus"` // 1 运行中，0 已停止
+	TriggerLastTime        int64  `json:"triggerLastTime"`
+	TriggerNextTime        int64  `json:"triggerNextTime"`
+}
+
+// Running 判断任务调度是否已启动
+func (j AdminJob) Running() bool {
+	return j.TriggerStatus == 1
+}
+
+// AdminJobQuery 任务查询条件，零值字段不参与过滤
+type AdminJobQuery struct {
+	JobGroup        int64  // 执行器 ID，0 表示全部
+	JobDesc         string // 任务描述（模糊匹配）
+	ExecutorHandler string // JobHandler（模糊匹配）
+	Author          string
+	TriggerStatus   int // -1 全部（默认），0 已停止，1 运行中
+	Start, Length   int // 分页，Length 为 0 时默认 100
+}
+
+// AdminExecutorGroup 调度中心执行器（执行器分组）信息
+type AdminExecutorGroup struct {
+	ID           int64    `json:"id"`
+	AppName      string   `json:"appname"`
+	Title        string   `json:"title"`
+	AddressType  int      `json:"addressType"` // 0 自动注册，1 手动录入
+	AddressList  string   `json:"addressList"`
+	RegistryList []string `json:"registryList"`
+}
+
+// AdminLogResult 任务日志分页内容
+type AdminLogResult struct {
+	FromLineNum int    `json:"fromLineNum"`
+	ToLineNum   int    `json:"toLineNum"`
+	LogContent  string `json:"logContent"`
+	End         bool   `json:"end"` // 任务已结束且日志已读取完毕
+}
+
+// adminReturn 调度中心通用返回结构
+type adminReturn struct {
+	Code    int             `json:"code"`
+	Msg     string          `json:"msg"`
+	Content json.RawMessage `json:"content"`
+}
+
+// adminPage 调度中心分页返回结构
+type adminPage struct {
+	RecordsTotal int             `json:"recordsTotal"`
+	Data         json.RawMessage `json:"data"`
+}
+
+// AdminClient 调度中心管理接口客户端
+// 使用管理端账号登录（会话 Cookie），登录过期时自动重新登录
+type AdminClient struct {
+	addr     string
+	username string
+	password string
+	client   *http.Client
+
+	loginMu sync.Mutex
+}
+
+// AdminOption AdminClient 选项函数
+type AdminOption func(*AdminClient)
+
+// WithAdminCredentials 设置管理端登录账号
+func WithAdminCredentials(username, password string) AdminOption {
+	return func(c *AdminClient) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithAdminTimeout 设置请求超时（默认 10s）
+func WithAdminTimeout(timeout time.Duration) AdminOption {
+	return func(c *AdminClient) {
+		if timeout > 0 {
+			c.client.Timeout = timeout
+		}
+	}
+}
+
+// NewAdminClient 创建调度中心管理接口客户端，addr 为调度中心地址（如 http://127.0.0.1:8080/xxl-job-admin）
+func NewAdminClient(addr string, opts ...AdminOption) (*AdminClient, error) {
+	addr = strings.TrimRight(strings.TrimSpace(addr), "/")
+	if addr == "" {
+		return nil, fmt.Errorf("xxl-job admin address cannot be empty")
+	}
+	if _, err := url.ParseRequestURI(addr); err != nil {
+		return nil, fmt.Errorf("invalid xxl-job admin address: %w", err)
+	}
+
+	jar, _ := cookiejar.New(nil)
+	c := &AdminClient{
+		addr: addr,
+		client: &http.Client{
+			Jar:     jar,
+			Timeout: defaultAdminTimeout,
+			// 未登录时调度中心重定向到登录页，不跟随重定向以便识别
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// NewAdminClientFromConfig 根据配置创建调度中心管理接口客户端
+func NewAdminClientFromConfig(cfg *Config) (*AdminClient, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	return NewAdminClient(cfg.ServerAddr,
+		WithAdminCredentials(cfg.Admin.Username, cfg.Admin.Password),
+		WithAdminTimeout(cfg.Admin.Timeout),
+	)
+}
+
+// Login 登录调度中心
+func (c *AdminClient) Login(ctx context.Context) error {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	if c.username == "" {
+		return fmt.Errorf("xxl-job admin username is required")
+	}
+	var ret adminReturn
+	err := c.doPost(ctx, "/login", url.Values{
+		"userName": {c.username},
+		"password": {c.password},
+	}, &ret)
+	if err != nil {
+		return fmt.Errorf("failed to login xxl-job admin: %w", err)
+	}
+	if ret.Code != xxl.SuccessCode {
+		return fmt.Errorf("failed to login xxl-job admin: %s", ret.Msg)
+	}
+	return nil
+}
+
+// ListJobs 分页查询任务，返回当前页的任务和总数
+func (c *AdminClient) ListJobs(ctx context.Context, query AdminJobQuery) ([]AdminJob, int, error) {
+	if query.Length <= 0 {
+		query.Length = 100
+	}
+	form := url.Values{
+		"jobGroup":        {strconv.FormatInt(query.JobGroup, 10)},
+		"triggerStatus":   {strconv.Itoa(query.TriggerStatus)},
+		"jobDesc":         {query.JobDesc},
+		"executorHandler": {query.ExecutorHandler},
+		"author":          {query.Author},
+		"start":           {strconv.Itoa(query.Start)},
+		"length":          {strconv.Itoa(query.Length)},
+	}
+
+	var page adminPage
+	if err := c.post(ctx, "/jobinfo/pageList", form, &page); err != nil {
+		return nil, 0, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	var jobs []AdminJob
+	if err := json.Unmarshal(page.Data, &jobs); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode jobs: %w", err)
+	}
+	return jobs, page.RecordsTotal, nil
+}
+
+// TriggerJob 立即触发一次任务，param 为空时使用任务配置的参数
+// 当前追踪上下文会通过请求头传递给调度中心
+func (c *AdminClient) TriggerJob(ctx context.Context, jobID int64, param string) error {
+	return c.call(ctx, "/jobinfo/trigger", url.Values{
+		"id":            {strconv.FormatInt(jobID, 10)},
+		"executorParam": {param},
+		"addressList":   {""},
+	})
+}
+
+// StartJob 启动任务调度
+func (c *AdminClient) StartJob(ctx context.Context, jobID int64) error {
+	return c.call(ctx, "/jobinfo/start", url.Values{"id": {strconv.FormatInt(jobID, 10)}})
+}
+
+// StopJob 停止任务调度
+func (c *AdminClient) StopJob(ctx context.Context, jobID int64) error {
+	return c.call(ctx, "/jobinfo/stop", url.Values{"id": {strconv.FormatInt(jobID, 10)}})
+}
+
+// LogDetail 从 fromLineNum 开始读取任务日志（行号从 1 开始）
+func (c *AdminClient) LogDetail(ctx context.Context, logID int64, fromLineNum int) (*AdminLogResult, error) {
+	var ret adminReturn
+	err := c.post(ctx, "/joblog/logDetailCat", url.Values{
+		"logId":       {strconv.FormatInt(logID, 10)},
+		"fromLineNum": {strconv.Itoa(fromLineNum)},
+	}, &ret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log %d: %w", logID, err)
+	}
+	if ret.Code != xxl.SuccessCode {
+		return nil, fmt.Errorf("failed to read log %d: %s", logID, ret.Msg)
+	}
+
+	var result AdminLogResult
+	if err := json.Unmarshal(ret.Content, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode log %d: %w", logID, err)
+	}
+	return &result, nil
+}
+
+// ListExecutors 查询执行器分组，appName 为空时返回全部
+func (c *AdminClient) ListExecutors(ctx context.Context, appName string) ([]AdminExecutorGroup, error) {
+	var page adminPage
+	err := c.post(ctx, "/jobgroup/pageList", url.Values{
+		"appname": {appName},
+		"start":   {"0"},
+		"length":  {"1000"},
+	}, &page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list executors: %w", err)
+	}
+	var groups []AdminExecutorGroup
+	if err := json.Unmarshal(page.Data, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode executors: %w", err)
+	}
+	return groups, nil
+}
+
+// call 调用返回 ReturnT 的接口，code 不为 200 时返回错误
+func (c *AdminClient) call(ctx context.Context, path string, form url.Values) error {
+	var ret adminReturn
+	if err := c.post(ctx, path, form, &ret); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if ret.Code != xxl.SuccessCode {
+		return fmt.Errorf("%s: %s", path, ret.Msg)
+	}
+	return nil
+}
+
+// post 发送请求，未登录或登录过期时登录后重试一次
+func (c *AdminClient) post(ctx context.Context, path string, form url.Values, out interface{}) error {
+	err := c.doPost(ctx, path, form, out)
+	if !errors.Is(err, errAdminUnauthorized) || c.username == "" {
+		return err
+	}
+	if err := c.Login(ctx); err != nil {
+		return err
+	}
+	return c.doPost(ctx, path, form, out)
+}
+
+// doPost 发送表单请求并解析 JSON 响应
+func (c *AdminClient) doPost(ctx context.Context, path string, form url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.addr+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	InjectTraceHeaders(ctx, req.Header)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		return errAdminUnauthorized
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAdminResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-anyway/framework-metrics"
+)
+
+const (
+	// defaultCircuitFailureThreshold 默认连续失败次数阈值
+	defaultCircuitFailureThreshold = 5
+	// defaultCircuitCoolDown 默认熔断冷却时间
+	defaultCircuitCoolDown = time.Minute
+)
+
+// ErrCircuitOpen 熔断器处于打开状态，任务未执行
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState 熔断器状态
+type CircuitState int
+
+const (
+	// CircuitClosed 关闭：正常执行
+	CircuitClosed CircuitState = iota
+	// CircuitOpen 打开：直接拒绝执行，直到冷却时间结束
+	CircuitOpen
+	// CircuitHalfOpen 半开：允许少量试探执行，成功后关闭，失败后重新打开
+	CircuitHalfOpen
+)
+
+// String 返回状态名称
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half_open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// MarshalText 以状态名称序列化
+func (s CircuitState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// CircuitBreakerConfig 熔断器配置
+type CircuitBreakerConfig struct {
+	FailureThreshold int           // 连续失败多少次后打开，默认 5
+	CoolDown         time.Duration // 打开后经过多久进入半开状态，默认 1m
+	HalfOpenMaxCalls int           // 半开状态下允许同时试探的执行数，默认 1
+	SuccessThreshold int           // 半开状态下连续成功多少次后关闭，默认 1
+}
+
+// CircuitBreakerState 熔断器状态快照
+type CircuitBreakerState struct {
+	Name                string       `json:"name"`                 // 熔断器名称
+	Key                 string       `json:"key"`                  // 熔断键（默认为任务名称）
+	State               CircuitState `json:"state"`                // 当前状态
+	ConsecutiveFailures int          `json:"consecutive_failures"` // 连续失败次数
+	OpenedAt            time.Time    `json:"opened_at,omitempty"`  // 最近一次打开时间
+	RetryAt             time.Time    `json:"retry_at,omitempty"`   // 打开状态下进入半开状态的时间
+}
+
+// CircuitBreaker 熔断器，按键（任务名称或自定义键）分别维护状态
+type CircuitBreaker struct {
+	name string
+	cfg  CircuitBreakerConfig
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit 单个键的熔断状态
+type circuit struct {
+	state      CircuitState
+	generation uint64 // 每次切换状态时递增，用于识别状态切换之前放行的执行
+	failures   int
+	successes  int
+	inFlight   int // 半开状态下正在试探的执行数
+	openedAt   time.Time
+}
+
+// circuitCall 放行的执行，记录放行时的状态代数
+type circuitCall struct {
+	key        string
+	generation uint64
+}
+
+// breakerRegistry 已创建的熔断器，用于健康状态展示
+var breakerRegistry struct {
+	mu       sync.Mutex
+	breakers []*CircuitBreaker
+}
+
+// NewCircuitBreaker 创建熔断器，状态会出现在执行器健康状态中
+func NewCircuitBreaker(name string, cfg CircuitBreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaultCircuitFailureThreshold
+	}
+	if cfg.CoolDown <= 0 {
+		cfg.CoolDown = defaultCircuitCoolDown
+	}
+	if cfg.HalfOpenMaxCalls <= 0 {
+		cfg.HalfOpenMaxCalls = 1
+	}
+	if cfg.SuccessThreshold <= 0 {
+		cfg.SuccessThreshold = 1
+	}
+
+	b := &CircuitBreaker{
+		name:     name,
+		cfg:      cfg,
+		circuits: make(map[string]*circuit),
+	}
+
+	breakerRegistry.mu.Lock()
+	breakerRegistry.breakers = append(breakerRegistry.breakers, b)
+	breakerRegistry.mu.Unlock()
+	return b
+}
+
+// allow 判断是否允许执行，允许时返回本次执行的凭证，执行结束后通过 record 记录结果
+// 拒绝时返回打开状态下进入半开状态的时间（半开状态下试探名额已满时为零值）
+func (b *CircuitBreaker) allow(key string) (circuitCall, bool, time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(key)
+	switch c.state {
+	case CircuitOpen:
+		retryAt := c.openedAt.Add(b.cfg.CoolDown)
+		if time.Now().Before(retryAt) {
+			return circuitCall{}, false, retryAt
+		}
+		b.transition(c, CircuitHalfOpen)
+		fallthrough
+	case CircuitHalfOpen:
+		if c.inFlight >= b.cfg.HalfOpenMaxCalls {
+			return circuitCall{}, false, time.Time{}
+		}
+		c.inFlight++
+	}
+	return circuitCall{key: key, generation: c.generation}, true, time.Time{}
+}
+
+// record 记录执行结果
+// 状态切换之前放行的执行（例如打开之前仍在运行的执行）结束时，状态已不同，其结果不影响当前状态，
+// 因此半开状态只由本次半开放行的试探执行决定
+func (b *CircuitBreaker) record(call circuitCall, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(call.key)
+	if call.generation != c.generation {
+		return
+	}
+	if c.state == CircuitHalfOpen {
+		c.inFlight--
+	}
+
+	if failed {
+		c.failures++
+		c.successes = 0
+		if c.state == CircuitHalfOpen || (c.state == CircuitClosed && c.failures >= b.cfg.FailureThreshold) {
+			c.openedAt = time.Now()
+			b.transition(c, CircuitOpen)
+		}
+		return
+	}
+
+	c.failures = 0
+	if c.state == CircuitHalfOpen {
+		c.successes++
+		if c.successes >= b.cfg.SuccessThreshold {
+			c.successes = 0
+			b.transition(c, CircuitClosed)
+		}
+	}
+}
+
+// circuit 获取键对应的熔断状态（调用方持有锁）
+func (b *CircuitBreaker) circuit(key string) *circuit {
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{state: CircuitClosed}
+		b.circuits[key] = c
+		if metrics.IsEnabled() {
+			xxlJobCircuits.WithLabelValues(b.name, CircuitClosed.String()).Inc()
+		}
+	}
+	return c
+}
+
+// transition 切换状态并更新指标（调用方持有锁）
+func (b *CircuitBreaker) transition(c *circuit, state CircuitState) {
+	if metrics.IsEnabled() {
+		xxlJobCircuits.WithLabelValues(b.name, c.state.String()).Dec()
+		xxlJobCircuits.WithLabelValues(b.name, state.String()).Inc()
+	}
+	c.state = state
+	c.generation++
+	c.inFlight = 0
+}
+
+// States 返回所有键的熔断状态快照（按键排序）
+func (b *CircuitBreaker) States() []CircuitBreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	states := make([]CircuitBreakerState, 0, len(b.circuits))
+	for key, c := range b.circuits {
+		state := CircuitBreakerState{
+			Name:                b.name,
+			Key:                 key,
+			State:               c.state,
+			ConsecutiveFailures: c.failures,
+			OpenedAt:            c.openedAt,
+		}
+		if c.state == CircuitOpen {
+			state.RetryAt = c.openedAt.Add(b.cfg.CoolDown)
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Key < states[j].Key })
+	return states
+}
+
+// circuitBreakerStates 返回所有熔断器的状态快照
+func circuitBreakerStates() []CircuitBreakerState {
+	breakerRegistry.mu.Lock()
+	breakers := append([]*CircuitBreaker(nil), breakerRegistry.breakers...)
+	breakerRegistry.mu.Unlock()
+
+	var states []CircuitBreakerState
+	for _, b := range breakers {
+		states = append(states, b.States()...)
+	}
+	return states
+}
+
+// CircuitKeyFunc 生成熔断键
+type CircuitKeyFunc func(ctx context.Context, param string) string
+
+// CircuitBreakerMiddleware 熔断中间件
+// 连续失败达到阈值后打开熔断，冷却期间不调用处理器，直接返回 ErrCircuitOpen，
+// 调度中心会收到以 CIRCUIT_OPEN 开头的执行结果。keyFn 为空时按任务名称熔断
+func CircuitBreakerMiddleware(breaker *CircuitBreaker, keyFn CircuitKeyFunc) Middleware {
+	if keyFn == nil {
+		keyFn = func(ctx context.Context, _ string) string {
+			info, _ := RunInfoFromContext(ctx)
+			return info.TaskName
+		}
+	}
+
+	return func(next TaskHandler) TaskHandler {
+		return func(ctx context.Context, param string) error {
+			key := keyFn(ctx, param)
+
+			call, allowed, retryAt := breaker.allow(key)
+			if !allowed {
+				if metrics.IsEnabled() {
+					xxlJobCircuitRejectedTotal.WithLabelValues(breaker.name).Inc()
+				}
+				if logWriter := JobLogFromContext(ctx); logWriter != nil {
+					logWriter.Warn("Task skipped: circuit breaker is open", "breaker", breaker.name, "key", key)
+				}
+				if retryAt.IsZero() {
+					return fmt.Errorf("%w: %s/%s is half-open", ErrCircuitOpen, breaker.name, key)
+				}
+				return fmt.Errorf("%w: %s/%s, retry after %s", ErrCircuitOpen, breaker.name, key, retryAt.Format(time.RFC3339))
+			}
+
+			// 处理器 panic 时按失败记录（同时释放半开状态的探测名额），panic 继续向外传递
+			returned := false
+			defer func() {
+				if !returned {
+					breaker.record(call, true)
+				}
+			}()
+
+			err := next(ctx, param)
+			returned = true
+			breaker.record(call, err != nil)
+			return err
+		}
+	}
+}
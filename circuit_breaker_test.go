@@ -0,0 +1,184 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// coolDown 让打开状态的冷却时间立即结束
+func coolDown(b *CircuitBreaker, key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.circuit(key).openedAt = time.Now().Add(-b.cfg.CoolDown)
+}
+
+// circuitState 返回键的当前状态
+func circuitState(b *CircuitBreaker, key string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.circuit(key).state
+}
+
+func TestCircuitBreakerStateMachine(t *testing.T) {
+	type step struct {
+		op   string // call、fail、cool
+		want CircuitState
+		ok   bool // call 是否放行
+	}
+	tests := []struct {
+		name  string
+		cfg   CircuitBreakerConfig
+		steps []step
+	}{
+		{
+			name: "opens after consecutive failures",
+			cfg:  CircuitBreakerConfig{FailureThreshold: 2},
+			steps: []step{
+				{op: "fail", want: CircuitClosed},
+				{op: "fail", want: CircuitOpen},
+				{op: "call", want: CircuitOpen, ok: false},
+			},
+		},
+		{
+			name: "success resets failures",
+			cfg:  CircuitBreakerConfig{FailureThreshold: 2},
+			steps: []step{
+				{op: "fail", want: CircuitClosed},
+				{op: "call", want: CircuitClosed, ok: true},
+				{op: "fail", want: CircuitClosed},
+			},
+		},
+		{
+			name: "half-open probe success closes",
+			cfg:  CircuitBreakerConfig{FailureThreshold: 1},
+			steps: []step{
+				{op: "fail", want: CircuitOpen},
+				{op: "cool", want: CircuitOpen},
+				{op: "call", want: CircuitClosed, ok: true},
+			},
+		},
+		{
+			name: "half-open probe failure reopens",
+			cfg:  CircuitBreakerConfig{FailureThreshold: 1},
+			steps: []step{
+				{op: "fail", want: CircuitOpen},
+				{op: "cool", want: CircuitOpen},
+				{op: "fail", want: CircuitOpen},
+				{op: "call", want: CircuitOpen, ok: false},
+			},
+		},
+		{
+			name: "success threshold",
+			cfg:  CircuitBreakerConfig{FailureThreshold: 1, SuccessThreshold: 2},
+			steps: []step{
+				{op: "fail", want: CircuitOpen},
+				{op: "cool", want: CircuitOpen},
+				{op: "call", want: CircuitHalfOpen, ok: true},
+				{op: "call", want: CircuitClosed, ok: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := NewCircuitBreaker(tt.name, tt.cfg)
+			handlerErr := errors.New("failed")
+			for i, s := range tt.steps {
+				called := false
+				var err error
+				switch s.op {
+				case "cool":
+					coolDown(breaker, "demo")
+				case "call", "fail":
+					handler := CircuitBreakerMiddleware(breaker, nil)(func(context.Context, string) error {
+						called = true
+						if s.op == "fail" {
+							return handlerErr
+						}
+						return nil
+					})
+					err = handler(lockTestContext("demo"), "")
+				}
+				if s.op == "call" {
+					if called != s.ok || (s.ok != (err == nil)) || (!s.ok && !errors.Is(err, ErrCircuitOpen)) {
+						t.Fatalf("step %d: called = %v, error = %v; want allowed %v", i, called, err, s.ok)
+					}
+				}
+				if got := circuitState(breaker, "demo"); got != s.want {
+					t.Fatalf("step %d (%s): state = %s, want %s", i, s.op, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+// 半开状态只由试探执行决定：打开之前放行、仍在运行的执行结束时不能关闭或重新打开熔断
+func TestCircuitBreakerIgnoresStaleCalls(t *testing.T) {
+	for _, staleFailed := range []bool{false, true} {
+		breaker := NewCircuitBreaker("stale", CircuitBreakerConfig{FailureThreshold: 1, HalfOpenMaxCalls: 1})
+
+		stale, ok, _ := breaker.allow("demo")
+		if !ok {
+			t.Fatal("closed breaker rejected call")
+		}
+		failing, _, _ := breaker.allow("demo")
+		breaker.record(failing, true)
+		coolDown(breaker, "demo")
+
+		probe, ok, _ := breaker.allow("demo")
+		if !ok || circuitState(breaker, "demo") != CircuitHalfOpen {
+			t.Fatal("probe not allowed after cool down")
+		}
+
+		breaker.record(stale, staleFailed)
+		if got := circuitState(breaker, "demo"); got != CircuitHalfOpen {
+			t.Fatalf("stale call (failed=%v) moved state to %s", staleFailed, got)
+		}
+		// 过期的结果不能释放试探名额
+		if _, ok, _ := breaker.allow("demo"); ok {
+			t.Fatal("second probe allowed while the first is running")
+		}
+
+		breaker.record(probe, false)
+		if got := circuitState(breaker, "demo"); got != CircuitClosed {
+			t.Fatalf("state after probe success = %s, want closed", got)
+		}
+	}
+}
+
+func TestCircuitBreakerPanicCountsAsFailure(t *testing.T) {
+	breaker := NewCircuitBreaker("panic", CircuitBreakerConfig{FailureThreshold: 1})
+	handler := CircuitBreakerMiddleware(breaker, nil)(func(context.Context, string) error {
+		panic("boom")
+	})
+
+	func() {
+		defer func() { _ = recover() }()
+		_ = handler(lockTestContext("demo"), "")
+	}()
+	if got := circuitState(breaker, "demo"); got != CircuitOpen {
+		t.Fatalf("state after panic = %s, want open", got)
+	}
+
+	states := breaker.States()
+	if len(states) != 1 || states[0].State != CircuitOpen || states[0].RetryAt.IsZero() {
+		t.Errorf("States = %+v", states)
+	}
+}
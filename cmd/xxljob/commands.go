@@ -0,0 +1,269 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	xxljob "github.com/go-anyway/framework-xxljob"
+)
+
+// jobsList 查询任务列表
+func jobsList(ctx context.Context, args []string) error {
+	fs, common := newFlagSet("jobs list")
+	app := fs.String("app", "", "executor app name (defaults to registry_key)")
+	group := fs.Int64("group", 0, "executor group id")
+	handler := fs.String("handler", "", "filter by job handler")
+	status := fs.String("status", "all", "all, running or stopped")
+	limit := fs.Int("limit", 100, "max jobs to list")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	query := xxljob.AdminJobQuery{
+		JobGroup:        *group,
+		ExecutorHandler: *handler,
+		Length:          *limit,
+	}
+	switch *status {
+	case "all":
+		query.TriggerStatus = -1
+	case "running":
+		query.TriggerStatus = 1
+	case "stopped":
+		query.TriggerStatus = 0
+	default:
+		return fmt.Errorf("%w: unknown status %q", errUsage, *status)
+	}
+
+	client, cfg, err := common.client()
+	if err != nil {
+		return err
+	}
+	if query.JobGroup == 0 {
+		if *app == "" {
+			*app = cfg.RegistryKey
+		}
+		if *app != "" {
+			if query.JobGroup, err = findGroupID(ctx, client, *app); err != nil {
+				return err
+			}
+		}
+	}
+
+	jobs, total, err := client.ListJobs(ctx, query)
+	if err != nil {
+		return err
+	}
+	if common.output == "json" {
+		return writeJSON(os.Stdout, jobs)
+	}
+
+	rows := make([][]string, 0, len(jobs))
+	for _, job := range jobs {
+		status := "stopped"
+		if job.Running() {
+			status = "running"
+		}
+		rows = append(rows, []string{
+			strconv.FormatInt(job.ID, 10),
+			strconv.FormatInt(job.JobGroup, 10),
+			job.ExecutorHandler,
+			job.JobDesc,
+			job.ScheduleType + " " + job.ScheduleConf,
+			status,
+			formatMillis(job.TriggerNextTime),
+		})
+	}
+	if err := writeTable(os.Stdout, []string{"ID", "GROUP", "HANDLER", "DESC", "SCHEDULE", "STATUS", "NEXT"}, rows); err != nil {
+		return err
+	}
+	if total > len(jobs) {
+		fmt.Fprintf(os.Stderr, "showing %d of %d jobs\n", len(jobs), total)
+	}
+	return nil
+}
+
+// findGroupID 根据 appname 查找执行器 ID
+func findGroupID(ctx context.Context, client *xxljob.AdminClient, app string) (int64, error) {
+	groups, err := client.ListExecutors(ctx, app)
+	if err != nil {
+		return 0, err
+	}
+	for _, group := range groups {
+		if group.AppName == app {
+			return group.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("executor %q not found", app)
+}
+
+// jobsTrigger 立即触发任务
+func jobsTrigger(ctx context.Context, args []string) error {
+	fs, common := newFlagSet("jobs trigger")
+	param := fs.String("param", "", "executor param (defaults to the job's param)")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(positional, "id")
+	if err != nil {
+		return err
+	}
+
+	client, _, err := common.client()
+	if err != nil {
+		return err
+	}
+	if err := client.TriggerJob(ctx, id, *param); err != nil {
+		return err
+	}
+	return writeResult(common.output, id, "triggered")
+}
+
+// jobsSetStatus 启动或停止任务调度
+func jobsSetStatus(ctx context.Context, args []string, start bool) error {
+	name, action := "jobs stop", "stopped"
+	if start {
+		name, action = "jobs start", "started"
+	}
+	fs, common := newFlagSet(name)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(positional, "id")
+	if err != nil {
+		return err
+	}
+
+	client, _, err := common.client()
+	if err != nil {
+		return err
+	}
+	if start {
+		err = client.StartJob(ctx, id)
+	} else {
+		err = client.StopJob(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+	return writeResult(common.output, id, action)
+}
+
+// writeResult 输出操作结果
+func writeResult(output string, id int64, action string) error {
+	if output == "json" {
+		return writeJSON(os.Stdout, map[string]interface{}{"id": id, "result": action})
+	}
+	fmt.Printf("job %d %s\n", id, action)
+	return nil
+}
+
+// logsTail 输出任务日志，--follow 时持续输出直到任务结束
+func logsTail(ctx context.Context, args []string) error {
+	fs, common := newFlagSet("logs tail")
+	from := fs.Int("from", 1, "first line number")
+	follow := fs.Bool("follow", false, "keep reading until the job finishes")
+	interval := fs.Duration("interval", time.Second, "poll interval with --follow")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	logID, err := parseID(positional, "logId")
+	if err != nil {
+		return err
+	}
+
+	client, _, err := common.client()
+	if err != nil {
+		return err
+	}
+
+	line := *from
+	for {
+		result, err := client.LogDetail(ctx, logID, line)
+		if err != nil {
+			return err
+		}
+		if common.output == "json" {
+			if err := writeJSONLine(os.Stdout, result); err != nil {
+				return err
+			}
+		} else if result.LogContent != "" {
+			fmt.Print(result.LogContent)
+			if !strings.HasSuffix(result.LogContent, "\n") {
+				fmt.Println()
+			}
+		}
+		if result.ToLineNum >= line {
+			line = result.ToLineNum + 1
+		}
+		if !*follow || result.End {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(*interval):
+		}
+	}
+}
+
+// executorsList 查询执行器列表
+func executorsList(ctx context.Context, args []string) error {
+	fs, common := newFlagSet("executors list")
+	app := fs.String("app", "", "filter by app name")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	client, _, err := common.client()
+	if err != nil {
+		return err
+	}
+	groups, err := client.ListExecutors(ctx, *app)
+	if err != nil {
+		return err
+	}
+	if common.output == "json" {
+		return writeJSON(os.Stdout, groups)
+	}
+
+	rows := make([][]string, 0, len(groups))
+	for _, group := range groups {
+		addressType := "auto"
+		if group.AddressType == 1 {
+			addressType = "manual"
+		}
+		rows = append(rows, []string{
+			strconv.FormatInt(group.ID, 10),
+			group.AppName,
+			group.Title,
+			addressType,
+			strings.Join(strings.Split(group.AddressList, ","), " "),
+		})
+	}
+	return writeTable(os.Stdout, []string{"ID", "APP", "TITLE", "TYPE", "ADDRESSES"}, rows)
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	xxljob "github.com/go-anyway/framework-xxljob"
+)
+
+// commonFlags 所有子命令共用的参数
+type commonFlags struct {
+	config   string
+	server   string
+	username string
+	password string
+	output   string
+}
+
+// newFlagSet 创建子命令参数集并注册通用参数
+func newFlagSet(name string) (*flag.FlagSet, *commonFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	common := &commonFlags{}
+	fs.StringVar(&common.config, "config", "", "YAML config file")
+	fs.StringVar(&common.server, "server", "", "admin address")
+	fs.StringVar(&common.username, "username", "", "admin username")
+	fs.StringVar(&common.password, "password", "", "admin password")
+	fs.StringVar(&common.output, "output", "table", "output format: table or json")
+	fs.StringVar(&common.output, "o", "table", "output format: table or json")
+	return fs, common
+}
+
+// parseFlags 解析参数，允许参数和位置参数交替出现（如 jobs trigger 12 --param x），返回位置参数
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// loadConfig 读取配置文件和环境变量，命令行参数优先
+func (c *commonFlags) loadConfig() (*xxljob.Config, error) {
+	cfg, err := xxljob.LoadConfig(c.config)
+	if err != nil {
+		return nil, err
+	}
+	overlay := func(dst *string, flagValue string) {
+		if flagValue != "" {
+			*dst = flagValue
+		}
+	}
+	overlay(&cfg.ServerAddr, c.server)
+	overlay(&cfg.Admin.Username, c.username)
+	overlay(&cfg.Admin.Password, c.password)
+	return cfg, nil
+}
+
+// client 创建调度中心客户端
+func (c *commonFlags) client() (*xxljob.AdminClient, *xxljob.Config, error) {
+	c.output = strings.ToLower(c.output)
+	switch c.output {
+	case "table", "json":
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown output format %q", errUsage, c.output)
+	}
+
+	cfg, err := c.loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.ServerAddr == "" {
+		return nil, nil, fmt.Errorf("%w: admin address is required (--server, server_addr or XXL_JOB_SERVER_ADDR)", errUsage)
+	}
+	client, err := xxljob.NewAdminClientFromConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, cfg, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Command xxljob 调度中心命令行工具
+//
+// 用法：
+//
+//	xxljob jobs list [--app NAME] [--group ID] [--handler NAME] [--status all|running|stopped] [flags]
+//	xxljob jobs trigger <id> [--param PARAM] [flags]
+//	xxljob jobs start <id> [flags]
+//	xxljob jobs stop <id> [flags]
+//	xxljob logs tail <logId> [--from LINE] [--follow] [--interval 1s] [flags]
+//	xxljob executors list [--app NAME] [flags]
+//
+// 通用参数：--config（YAML 配置文件，与执行器相同）、--server、--username、--password、--output table|json。
+// 也可以通过 XXL_JOB_SERVER_ADDR、XXL_JOB_ADMIN_USERNAME、XXL_JOB_ADMIN_PASSWORD 等环境变量配置，
+// 密码等密钥也可以通过 *_FILE 变量从文件读取（例如 XXL_JOB_ADMIN_PASSWORD_FILE）
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+)
+
+// errUsage 参数错误
+var errUsage = errors.New("usage error")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+			if !errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+const usage = `Usage:
+  xxljob jobs list [--app NAME] [--group ID] [--handler NAME] [--status all|running|stopped] [--limit N] [flags]
+  xxljob jobs trigger <id> [--param PARAM] [flags]
+  xxljob jobs start <id> [flags]
+  xxljob jobs stop <id> [flags]
+  xxljob logs tail <logId> [--from LINE] [--follow] [--interval 1s] [flags]
+  xxljob executors list [--app NAME] [flags]
+
+Common flags (accepted by every command):
+  --config PATH       YAML config file (same as the executor)
+  --server ADDR       admin address, overrides server_addr
+  --username NAME     admin username, overrides admin.username
+  --password PASS     admin password, overrides admin.password
+  -o, --output FMT    output format: table (default) or json
+`
+
+// run 解析子命令并执行
+func run(ctx context.Context, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("%w: missing command", errUsage)
+	}
+
+	switch args[0] + " " + args[1] {
+	case "jobs list":
+		return jobsList(ctx, args[2:])
+	case "jobs trigger":
+		return jobsTrigger(ctx, args[2:])
+	case "jobs start":
+		return jobsSetStatus(ctx, args[2:], true)
+	case "jobs stop":
+		return jobsSetStatus(ctx, args[2:], false)
+	case "logs tail":
+		return logsTail(ctx, args[2:])
+	case "executors list":
+		return executorsList(ctx, args[2:])
+	default:
+		return fmt.Errorf("%w: unknown command %q", errUsage, args[0]+" "+args[1])
+	}
+}
+
+// parseID 解析位置参数中的 ID
+func parseID(args []string, name string) (int64, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("%w: expected <%s>", errUsage, name)
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: invalid %s %q", errUsage, name, args[0])
+	}
+	return id, nil
+}
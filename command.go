@@ -0,0 +1,206 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPartialLineSize 不完整行的最大缓存大小（64KB），超过后强制输出
+const maxPartialLineSize = 64 * 1024
+
+// lineWriter 按行切分的 io.Writer 实现
+// 适用于将子进程输出或基于 io.Writer 的第三方日志接入任务日志
+type lineWriter struct {
+	prefix string
+	emit   func(line string)
+	buf    bytes.Buffer
+	mu     sync.Mutex
+}
+
+// newLineWriter 创建按行切分的写入器
+func newLineWriter(prefix string, emit func(line string)) *lineWriter {
+	return &lineWriter{
+		prefix: prefix,
+		emit:   emit,
+	}
+}
+
+// Write 写入数据，每遇到一个换行符输出一行
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(p)
+	for {
+		idx := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if idx < 0 {
+			break
+		}
+		line := string(w.buf.Next(idx + 1))
+		w.emitLine(line)
+	}
+
+	// 超长的不完整行强制输出，避免缓存无限增长
+	if w.buf.Len() >= maxPartialLineSize {
+		w.emitLine(w.buf.String())
+		w.buf.Reset()
+	}
+	return len(p), nil
+}
+
+// Close 输出剩余的不完整行
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buf.Len() > 0 {
+		w.emitLine(w.buf.String())
+		w.buf.Reset()
+	}
+	return nil
+}
+
+// emitLine 去除行尾换行并添加前缀后输出（调用方需持有锁）
+func (w *lineWriter) emitLine(line string) {
+	line = strings.TrimRight(line, "\r\n")
+	if w.prefix != "" {
+		line = w.prefix + line
+	}
+	w.emit(line)
+}
+
+// RunCommand 运行外部命令，并将 stdout/stderr 按行写入任务日志
+// stdout 以 INFO 级别、stderr 以 WARN 级别写入，分别带有 [stdout]、[stderr] 前缀；
+// 如果 cmd 已设置 Stdout/Stderr，输出会同时写入原有的 Writer。
+// 不在任务上下文中时，输出写入 LoggerFromContext(ctx)。
+// ctx 被取消时会杀死整个进程组（非 Unix 平台仅杀死子进程本身），并返回 ctx.Err()。
+func RunCommand(ctx context.Context, cmd *exec.Cmd) error {
+	if cmd == nil {
+		return fmt.Errorf("command cannot be nil")
+	}
+
+	stdout := commandOutputWriter(ctx, LogLevelInfo, "[stdout] ")
+	stderr := commandOutputWriter(ctx, LogLevelWarn, "[stderr] ")
+	defer stdout.Close()
+	defer stderr.Close()
+
+	if cmd.Stdout != nil {
+		cmd.Stdout = io.MultiWriter(cmd.Stdout, stdout)
+	} else {
+		cmd.Stdout = stdout
+	}
+	if cmd.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, stderr)
+	} else {
+		cmd.Stderr = stderr
+	}
+	setProcessGroup(cmd)
+
+	writer := JobLogFromContext(ctx)
+	if writer != nil {
+		writer.Info("Running command", "command", cmd.String())
+	}
+
+	startTime := time.Now()
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start command: %w", err)
+	}
+
+	// 监听 ctx 取消，杀死进程组
+	// Wait 返回后进程已被回收，pgid 可能被复用，因此在锁内检查 finished 后才发送信号
+	var (
+		mu       sync.Mutex
+		finished bool
+	)
+	done := make(chan struct{})
+	killed := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			mu.Lock()
+			defer mu.Unlock()
+			if !finished {
+				killProcessGroup(cmd)
+				close(killed)
+			}
+		case <-done:
+		}
+	}()
+
+	err := cmd.Wait()
+	mu.Lock()
+	finished = true
+	mu.Unlock()
+	close(done)
+	duration := time.Since(startTime)
+
+	// 输出剩余的不完整行，保证其出现在结束日志之前
+	_ = stdout.Close()
+	_ = stderr.Close()
+
+	select {
+	case <-killed:
+		if writer != nil {
+			writer.Warn("Command killed", "command", cmd.String(), "duration", duration, "reason", ctx.Err())
+		}
+		return ctx.Err()
+	default:
+	}
+
+	if err != nil {
+		var exitErr *exec.ExitError
+		if writer != nil {
+			if errors.As(err, &exitErr) {
+				writer.Error("Command failed", "command", cmd.String(), "exit_code", exitErr.ExitCode(), "duration", duration)
+			} else {
+				writer.Error("Command failed", "command", cmd.String(), "duration", duration, "error", err)
+			}
+		}
+		return fmt.Errorf("command failed: %w", err)
+	}
+
+	if writer != nil {
+		writer.Info("Command completed", "command", cmd.String(), "duration", duration)
+	}
+	return nil
+}
+
+// commandOutputWriter 创建命令输出写入器
+// 优先写入任务日志，不存在时写入 Logger
+func commandOutputWriter(ctx context.Context, level LogLevel, prefix string) io.WriteCloser {
+	if writer := JobLogFromContext(ctx); writer != nil {
+		return writer.Writer(level, prefix)
+	}
+
+	logger := LoggerFromContext(ctx)
+	return newLineWriter(prefix, func(line string) {
+		if level >= LogLevelWarn {
+			logger.Warn(line)
+		} else {
+			logger.Info(line)
+		}
+	})
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+//go:build !unix
+
+package xxljob
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup 非 Unix 平台不支持进程组，保持默认行为
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup 非 Unix 平台仅杀死子进程本身
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	_ = cmd.Process.Kill()
+}
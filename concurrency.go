@@ -0,0 +1,311 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-anyway/framework-metrics"
+)
+
+// ErrConcurrencyLimit 并发数已达上限
+var ErrConcurrencyLimit = errors.New("concurrency limit reached")
+
+// ConcurrencyMode 达到并发上限时的处理方式
+type ConcurrencyMode int
+
+const (
+	// ConcurrencyReject 直接拒绝（默认）
+	ConcurrencyReject ConcurrencyMode = iota
+	// ConcurrencyWait 排队等待空闲，超过等待时间后拒绝
+	// 调度中心的请求在应答之前排队，等待超时时返回失败状态码，
+	// 因此 WaitTimeout 应小于调度中心的调度请求超时时间，否则调度中心会先按超时处理
+	ConcurrencyWait
+)
+
+// String 返回处理方式名称
+func (m ConcurrencyMode) String() string {
+	switch m {
+	case ConcurrencyReject:
+		return "reject"
+	case ConcurrencyWait:
+		return "wait"
+	default:
+		return fmt.Sprintf("ConcurrencyMode(%d)", int(m))
+	}
+}
+
+// ParseConcurrencyMode 解析并发上限处理方式，空字符串返回 ConcurrencyReject
+func ParseConcurrencyMode(name string) (ConcurrencyMode, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "reject":
+		return ConcurrencyReject, nil
+	case "wait":
+		return ConcurrencyWait, nil
+	default:
+		return ConcurrencyReject, fmt.Errorf("unknown concurrency mode: %s", name)
+	}
+}
+
+// ConcurrencyConfig 并发限制配置
+type ConcurrencyConfig struct {
+	Limit       int           `yaml:"limit"`        // 最大并发数，0 表示不限制
+	Mode        string        `yaml:"mode"`         // reject、wait
+	WaitTimeout time.Duration `yaml:"wait_timeout"` // wait 模式下的最长等待时间，0 表示一直等待到调度请求或任务取消
+}
+
+// Validate 验证并发限制配置
+func (c ConcurrencyConfig) Validate() error {
+	if c.Limit < 0 {
+		return fmt.Errorf("limit must be >= 0")
+	}
+	if c.WaitTimeout < 0 {
+		return fmt.Errorf("wait_timeout must be >= 0")
+	}
+	if _, err := ParseConcurrencyMode(c.Mode); err != nil {
+		return err
+	}
+	return nil
+}
+
+// concurrencyLimiter 基于信号量的并发限制器，nil 表示不限制
+type concurrencyLimiter struct {
+	sem     chan struct{}
+	mode    ConcurrencyMode
+	timeout time.Duration
+	waiting atomic.Int64
+}
+
+// newConcurrencyLimiter 创建并发限制器，未设置上限时返回 nil
+func newConcurrencyLimiter(cfg ConcurrencyConfig) *concurrencyLimiter {
+	if cfg.Limit <= 0 {
+		return nil
+	}
+	mode, _ := ParseConcurrencyMode(cfg.Mode)
+	return &concurrencyLimiter{
+		sem:     make(chan struct{}, cfg.Limit),
+		mode:    mode,
+		timeout: cfg.WaitTimeout,
+	}
+}
+
+// acquire 获取执行槽位，失败时返回 ErrConcurrencyLimit 或 ctx 错误
+func (l *concurrencyLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	select {
+	case l.sem <- struct{}{}:
+		return nil
+	default:
+	}
+	if l.mode == ConcurrencyReject {
+		return ErrConcurrencyLimit
+	}
+
+	l.waiting.Add(1)
+	defer l.waiting.Add(-1)
+
+	var timeout <-chan time.Time
+	if l.timeout > 0 {
+		timer := time.NewTimer(l.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case l.sem <- struct{}{}:
+		return nil
+	case <-timeout:
+		return fmt.Errorf("%w: waited %s", ErrConcurrencyLimit, l.timeout)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release 释放执行槽位
+func (l *concurrencyLimiter) release() {
+	if l != nil {
+		<-l.sem
+	}
+}
+
+// full 判断是否已达到并发上限
+func (l *concurrencyLimiter) full() bool {
+	return l != nil && len(l.sem) >= cap(l.sem)
+}
+
+// concurrencyLimits 执行器级和任务级并发限制
+type concurrencyLimits struct {
+	global *concurrencyLimiter
+
+	mu       sync.RWMutex
+	tasks    map[string]*concurrencyLimiter
+	jobTasks map[int64]string // 调度中心任务 ID 到任务名称的映射，用于忙碌检测
+	running  map[string]int
+	held     map[int64]func() // 调度请求已占用、尚未开始执行的槽位（按日志 ID）
+}
+
+// newConcurrencyLimits 根据配置创建并发限制
+func newConcurrencyLimits(global ConcurrencyConfig, tasks map[string]ConcurrencyConfig) *concurrencyLimits {
+	l := &concurrencyLimits{
+		global:   newConcurrencyLimiter(global),
+		tasks:    make(map[string]*concurrencyLimiter, len(tasks)),
+		jobTasks: make(map[int64]string),
+		running:  make(map[string]int),
+		held:     make(map[int64]func()),
+	}
+	for name, cfg := range tasks {
+		if limiter := newConcurrencyLimiter(cfg); limiter != nil {
+			l.tasks[name] = limiter
+		}
+	}
+	return l
+}
+
+// setTaskLimit 设置任务级并发限制（覆盖配置），正在执行的任务仍占用原限制器的槽位
+func (l *concurrencyLimits) setTaskLimit(taskName string, cfg ConcurrencyConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limiter := newConcurrencyLimiter(cfg); limiter != nil {
+		l.tasks[taskName] = limiter
+	} else {
+		delete(l.tasks, taskName)
+	}
+}
+
+// taskLimiter 获取任务级并发限制器，未限制时返回 nil
+func (l *concurrencyLimits) taskLimiter(taskName string) *concurrencyLimiter {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.tasks[taskName]
+}
+
+// acquire 获取任务执行槽位（先任务级后执行器级，避免排队的任务占用全局槽位）
+// 返回的函数用于释放槽位
+func (l *concurrencyLimits) acquire(ctx context.Context, taskName string) (release func(), err error) {
+	task := l.taskLimiter(taskName)
+
+	l.observeWaiting(taskName, task, 1)
+	err = task.acquire(ctx)
+	if err == nil {
+		if err = l.global.acquire(ctx); err != nil {
+			task.release()
+		}
+	}
+	l.observeWaiting(taskName, task, -1)
+
+	if err != nil {
+		if metrics.IsEnabled() && errors.Is(err, ErrConcurrencyLimit) {
+			xxlJobRejectedTotal.WithLabelValues(taskName).Inc()
+		}
+		return nil, err
+	}
+
+	l.mu.Lock()
+	l.running[taskName]++
+	l.mu.Unlock()
+	if metrics.IsEnabled() {
+		xxlJobRunning.WithLabelValues(taskName).Inc()
+	}
+
+	return func() {
+		l.mu.Lock()
+		l.running[taskName]--
+		l.mu.Unlock()
+		if metrics.IsEnabled() {
+			xxlJobRunning.WithLabelValues(taskName).Dec()
+		}
+		l.global.release()
+		task.release()
+	}, nil
+}
+
+// observeWaiting 更新排队数量指标
+func (l *concurrencyLimits) observeWaiting(taskName string, task *concurrencyLimiter, delta float64) {
+	if metrics.IsEnabled() && (l.global != nil || task != nil) {
+		xxlJobWaiting.WithLabelValues(taskName).Add(delta)
+	}
+}
+
+// hold 保存调度请求占用的槽位，由执行该日志 ID 的任务通过 take 取走
+func (l *concurrencyLimits) hold(logID int64, release func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.held[logID] = release
+}
+
+// take 取走日志 ID 对应的已占用槽位，没有时返回 nil
+func (l *concurrencyLimits) take(logID int64) func() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	release := l.held[logID]
+	delete(l.held, logID)
+	return release
+}
+
+// drop 释放尚未被任务取走的已占用槽位（SDK 未接受调度请求时）
+func (l *concurrencyLimits) drop(logID int64) {
+	if release := l.take(logID); release != nil {
+		release()
+	}
+}
+
+// busy 判断任务是否已达到任务级或执行器级并发上限
+func (l *concurrencyLimits) busy(taskName string) bool {
+	return l.global.full() || l.taskLimiter(taskName).full()
+}
+
+// rememberJob 记录调度中心任务 ID 对应的任务名称
+func (l *concurrencyLimits) rememberJob(jobID int64, taskName string) {
+	if jobID <= 0 || taskName == "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.jobTasks[jobID] = taskName
+}
+
+// jobBusy 根据调度中心任务 ID 判断是否已达到并发上限，未知任务只检查执行器级上限
+func (l *concurrencyLimits) jobBusy(jobID int64) bool {
+	l.mu.RLock()
+	taskName := l.jobTasks[jobID]
+	l.mu.RUnlock()
+	return l.busy(taskName)
+}
+
+// Running 返回各任务当前正在执行的数量
+func (l *concurrencyLimits) Running() map[string]int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := make(map[string]int, len(l.running))
+	for name, n := range l.running {
+		if n > 0 {
+			result[name] = n
+		}
+	}
+	return result
+}
@@ -0,0 +1,316 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	xxl "github.com/xxl-job/xxl-job-executor-go"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ConcurrencyConfig
+		ctx     func() (context.Context, context.CancelFunc)
+		release bool // 等待期间释放已占用的槽位
+		wantErr error
+	}{
+		{
+			name:    "reject when full",
+			cfg:     ConcurrencyConfig{Limit: 1},
+			wantErr: ErrConcurrencyLimit,
+		},
+		{
+			name:    "wait until released",
+			cfg:     ConcurrencyConfig{Limit: 1, Mode: "wait", WaitTimeout: 5 * time.Second},
+			release: true,
+		},
+		{
+			name:    "wait timeout",
+			cfg:     ConcurrencyConfig{Limit: 1, Mode: "wait", WaitTimeout: 20 * time.Millisecond},
+			wantErr: ErrConcurrencyLimit,
+		},
+		{
+			name: "wait cancelled",
+			cfg:  ConcurrencyConfig{Limit: 1, Mode: "wait"},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := newConcurrencyLimiter(tt.cfg)
+			if err := limiter.acquire(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if !limiter.full() {
+				t.Fatal("limiter not full after acquiring the only slot")
+			}
+
+			ctx, cancel := context.Background(), context.CancelFunc(func() {})
+			if tt.ctx != nil {
+				ctx, cancel = tt.ctx()
+			}
+			defer cancel()
+			if tt.release {
+				time.AfterFunc(20*time.Millisecond, limiter.release)
+			}
+
+			err := limiter.acquire(ctx)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("acquire error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if limiter := newConcurrencyLimiter(ConcurrencyConfig{}); limiter != nil || limiter.full() {
+		t.Error("zero config should not limit")
+	}
+}
+
+// 排队等待任务级槽位的调度不能占用执行器级槽位
+func TestConcurrencyLimitsTaskBeforeGlobal(t *testing.T) {
+	limits := newConcurrencyLimits(
+		ConcurrencyConfig{Limit: 2},
+		map[string]ConcurrencyConfig{"a": {Limit: 1, Mode: "wait"}},
+	)
+
+	releaseA, err := limits.acquire(context.Background(), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queued := make(chan error, 1)
+	go func() {
+		release, err := limits.acquire(ctx, "a")
+		if err == nil {
+			release()
+		}
+		queued <- err
+	}()
+
+	releaseB, err := limits.acquire(context.Background(), "b")
+	if err != nil {
+		t.Fatalf("task b rejected while task a is queued: %v", err)
+	}
+	if !limits.busy("b") || !limits.busy("a") {
+		t.Error("executor should be busy with both global slots taken")
+	}
+	if got := limits.Running(); got["a"] != 1 || got["b"] != 1 {
+		t.Errorf("Running = %v, want a=1 b=1", got)
+	}
+
+	releaseB()
+	releaseA()
+	if err := <-queued; err != nil {
+		t.Errorf("queued acquire error = %v", err)
+	}
+	if got := limits.Running(); len(got) != 0 {
+		t.Errorf("Running after release = %v, want empty", got)
+	}
+}
+
+// blockingTask 注册阻塞直到 release 关闭的任务，返回已开始执行的通知
+func blockingTask(t *testing.T, e *executorImpl, taskName string, release <-chan struct{}, opts ...TaskOption) <-chan int64 {
+	t.Helper()
+	started := make(chan int64, 10)
+	err := e.RegTask(taskName, func(ctx context.Context, _ string) error {
+		info, _ := RunInfoFromContext(ctx)
+		started <- info.LogID
+		<-release
+		return nil
+	}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return started
+}
+
+func TestHandleRunConcurrencyReject(t *testing.T) {
+	admin, callbacks := callbackAdmin(t)
+	e := newTestExecutor(t, admin)
+	release := make(chan struct{})
+	started := blockingTask(t, e, "demo", release, TaskConcurrencyLimit(ConcurrencyConfig{Limit: 1}))
+
+	if ret := postAdmin(t, e.handleRun, runRequest("demo", 1, 1)); ret.Code != int(xxl.SuccessCode) {
+		t.Fatalf("first run = %+v, want success", ret)
+	}
+	<-started
+
+	// 另一个调度中心任务 ID 调度同一处理器，任务级上限已满，直接返回失败码
+	ret := postAdmin(t, e.handleRun, runRequest("demo", 2, 2))
+	if ret.Code != int(xxl.FailureCode) || !strings.Contains(ret.Msg, "concurrency limit reached") {
+		t.Fatalf("second run = %+v, want concurrency failure", ret)
+	}
+	if record, ok := historyRecord(e, "demo", 2); !ok || record.Status != ExecutionRejected {
+		t.Errorf("history for rejected run = %+v, %v", record, ok)
+	}
+
+	// 忙碌检测：达到上限时返回忙碌，包括调度中心的其他任务 ID
+	for _, jobID := range []int64{1, 2} {
+		if ret := postAdmin(t, e.handleIdleBeat, map[string]int64{"jobId": jobID}); ret.Code != int(xxl.FailureCode) {
+			t.Errorf("idleBeat job %d while busy = %+v, want failure", jobID, ret)
+		}
+	}
+
+	close(release)
+	waitFor(t, "task to finish", func() bool { return len(e.limits.Running()) == 0 })
+	if logID := <-callbacks; logID != 1 {
+		t.Fatalf("callback for log %d, want 1", logID)
+	}
+
+	if ret := postAdmin(t, e.handleRun, runRequest("demo", 2, 3)); ret.Code != int(xxl.SuccessCode) {
+		t.Fatalf("run after release = %+v, want success", ret)
+	}
+	if logID := <-started; logID != 3 {
+		t.Errorf("started log %d, want 3", logID)
+	}
+}
+
+// SDK 为同一处理器复用同一个任务对象，这里使用执行器级上限在两个任务之间排队
+func TestHandleRunConcurrencyWait(t *testing.T) {
+	e := newTestExecutor(t, WithConcurrency(ConcurrencyConfig{Limit: 1, Mode: "wait", WaitTimeout: 30 * time.Millisecond}))
+	releaseA, releaseB := make(chan struct{}), make(chan struct{})
+	defer close(releaseB)
+	startedA := blockingTask(t, e, "a", releaseA)
+	startedB := blockingTask(t, e, "b", releaseB)
+
+	postAdmin(t, e.handleRun, runRequest("a", 1, 1))
+	<-startedA
+
+	// 等待超时后返回失败码，而不是先应答成功再通过回调上报
+	ret := postAdmin(t, e.handleRun, runRequest("b", 2, 2))
+	if ret.Code != int(xxl.FailureCode) || !strings.Contains(ret.Msg, "waited") {
+		t.Fatalf("run after wait timeout = %+v, want concurrency failure", ret)
+	}
+
+	// 等待期间槽位释放时，调度请求成功并使用该槽位执行
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var waited adminReturn
+	go func() {
+		defer wg.Done()
+		waited = postAdmin(t, e.handleRun, runRequest("b", 2, 3))
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(releaseA)
+	wg.Wait()
+	if waited.Code != int(xxl.SuccessCode) {
+		t.Fatalf("run queued while slot released = %+v, want success", waited)
+	}
+	if logID := <-startedB; logID != 3 {
+		t.Errorf("started log %d, want 3", logID)
+	}
+}
+
+// SDK 拒绝调度请求时，handleRun 占用的槽位必须释放
+func TestHandleRunReleasesSlotWhenSDKRejects(t *testing.T) {
+	e := newTestExecutor(t)
+	release := make(chan struct{})
+	defer close(release)
+	started := blockingTask(t, e, "demo", release, TaskConcurrencyLimit(ConcurrencyConfig{Limit: 2}))
+
+	postAdmin(t, e.handleRun, runRequest("demo", 1, 1))
+	<-started
+
+	// 单机串行策略下同一任务 ID 仍在运行，SDK 返回失败
+	if ret := postAdmin(t, e.handleRun, runRequest("demo", 1, 2)); ret.Code != int(xxl.FailureCode) {
+		t.Fatalf("duplicate run = %+v, want SDK failure", ret)
+	}
+	if got := e.limits.Running()["demo"]; got != 1 {
+		t.Errorf("running = %d, want 1", got)
+	}
+	e.limits.mu.RLock()
+	held := len(e.limits.held)
+	e.limits.mu.RUnlock()
+	if held != 0 {
+		t.Errorf("%d slots still held", held)
+	}
+}
+
+func TestRegisterTaskConcurrency(t *testing.T) {
+	configured := ConcurrencyConfig{Limit: 1}
+	handler := func(context.Context, string) error { return nil }
+	limit := func(e *executorImpl) int {
+		if limiter := e.limits.taskLimiter("demo"); limiter != nil {
+			return cap(limiter.sem)
+		}
+		return 0
+	}
+
+	t.Run("configured limit", func(t *testing.T) {
+		e := newTestExecutor(t, WithTaskConcurrency("demo", configured))
+		if err := e.RegTask("demo", handler); err != nil {
+			t.Fatal(err)
+		}
+		info, _ := e.registry.Get("demo")
+		if info.Concurrency != configured || limit(e) != 1 {
+			t.Errorf("Concurrency = %+v, limit %d; want configured limit", info.Concurrency, limit(e))
+		}
+	})
+
+	t.Run("zero option disables configured limit", func(t *testing.T) {
+		e := newTestExecutor(t, WithTaskConcurrency("demo", configured))
+		if err := e.RegTask("demo", handler, TaskConcurrencyLimit(ConcurrencyConfig{})); err != nil {
+			t.Fatal(err)
+		}
+		info, _ := e.registry.Get("demo")
+		if info.Concurrency != (ConcurrencyConfig{}) || limit(e) != 0 {
+			t.Errorf("Concurrency = %+v, limit %d; want unlimited", info.Concurrency, limit(e))
+		}
+
+		// 注销后恢复配置中的限制
+		if err := e.UnregisterTask("demo"); err != nil {
+			t.Fatal(err)
+		}
+		if limit(e) != 1 {
+			t.Errorf("limit after unregister = %d, want 1", limit(e))
+		}
+	})
+
+	t.Run("replace keeps limiter when unchanged", func(t *testing.T) {
+		e := newTestExecutor(t)
+		opt := TaskConcurrencyLimit(ConcurrencyConfig{Limit: 2})
+		if err := e.RegTask("demo", handler, opt); err != nil {
+			t.Fatal(err)
+		}
+		before := e.limits.taskLimiter("demo")
+		if err := e.ReplaceTask("demo", handler, opt); err != nil {
+			t.Fatal(err)
+		}
+		if e.limits.taskLimiter("demo") != before {
+			t.Error("limiter replaced although the limit did not change")
+		}
+
+		if err := e.ReplaceTask("demo", handler, TaskConcurrencyLimit(ConcurrencyConfig{Limit: 3})); err != nil {
+			t.Fatal(err)
+		}
+		if limit(e) != 3 {
+			t.Errorf("limit after replace = %d, want 3", limit(e))
+		}
+	})
+}
@@ -0,0 +1,284 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	yaml "go.yaml.in/yaml/v2"
+)
+
+// envFileSuffix 密钥文件环境变量后缀，例如 XXL_JOB_ACCESS_TOKEN_FILE=/run/secrets/xxl_job_token
+const envFileSuffix = "_FILE"
+
+// durationType time.Duration 类型，环境变量和 default 标签按 time.ParseDuration 解析
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// yamlLinePrefix yaml 错误信息中的行号前缀，读取子节点时行号与原文件不一致，需要去掉
+var yamlLinePrefix = regexp.MustCompile(`^line \d+: `)
+
+// LoadOption LoadConfig 选项
+type LoadOption func(*loadOptions)
+
+// loadOptions LoadConfig 选项
+type loadOptions struct {
+	lookupEnv func(string) (string, bool)
+	section   string
+}
+
+// WithEnvLookup 设置环境变量查询函数（默认 os.LookupEnv），传入 nil 表示不读取环境变量
+func WithEnvLookup(lookup func(string) (string, bool)) LoadOption {
+	return func(o *loadOptions) {
+		o.lookupEnv = lookup
+	}
+}
+
+// WithConfigSection 从 YAML 文件的指定节点读取配置，多级节点用 . 分隔（例如 jobs.xxl_job）
+// 用于执行器配置嵌在应用配置文件中的场景，节点之外的内容不做检查
+func WithConfigSection(section string) LoadOption {
+	return func(o *loadOptions) {
+		o.section = section
+	}
+}
+
+// LoadConfig 加载执行器配置
+// 依次应用 default 标签、YAML 文件（path 为空时跳过）和 XXL_JOB_* 环境变量，后者优先。
+// 环境变量未设置时读取对应的 *_FILE 变量指向的文件内容（去掉末尾换行），用于挂载的密钥文件。
+// 启用（Enabled）时检查 required 标签并验证配置；YAML 未知字段、类型错误和验证错误合并后一次返回
+func LoadConfig(path string, opts ...LoadOption) (*Config, error) {
+	o := &loadOptions{lookupEnv: os.LookupEnv}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	cfg := &Config{}
+	root := reflect.ValueOf(cfg).Elem()
+	errs := applyDefaults(root, "")
+	if path != "" {
+		fileErrs, err := loadYAMLConfig(cfg, path, o.section)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, fileErrs...)
+	}
+	if o.lookupEnv != nil {
+		errs = append(errs, applyEnv(root, o.lookupEnv)...)
+	}
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadYAMLConfig 读取 YAML 配置文件
+// 文件无法读取或解析时返回 err；未知字段和类型错误逐项返回，其余字段照常读取
+func loadYAMLConfig(cfg *Config, path, section string) ([]error, error) {
+	// #nosec G304 -- 配置文件路径由调用方指定
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read xxl-job config: %w", err)
+	}
+
+	stripLines := false
+	if section != "" {
+		if data, err = yamlSection(data, section); err != nil {
+			return nil, fmt.Errorf("failed to parse xxl-job config %s: %w", path, err)
+		}
+		stripLines = true
+	}
+
+	err = yaml.UnmarshalStrict(data, cfg)
+	var typeErr *yaml.TypeError
+	if err != nil && !errors.As(err, &typeErr) {
+		return nil, fmt.Errorf("failed to parse xxl-job config %s: %w", path, err)
+	}
+	if typeErr == nil {
+		return nil, nil
+	}
+	errs := make([]error, 0, len(typeErr.Errors))
+	for _, msg := range typeErr.Errors {
+		if stripLines {
+			msg = yamlLinePrefix.ReplaceAllString(msg, "")
+		}
+		errs = append(errs, fmt.Errorf("xxl-job config %s: %s", path, msg))
+	}
+	return errs, nil
+}
+
+// yamlSection 取出 YAML 文档中的指定节点并重新编码
+func yamlSection(data []byte, section string) ([]byte, error) {
+	var node interface{}
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	for _, key := range strings.Split(section, ".") {
+		m, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("section %q not found", section)
+		}
+		if node, ok = m[key]; !ok {
+			return nil, fmt.Errorf("section %q not found", section)
+		}
+	}
+	if node == nil {
+		return nil, nil
+	}
+	return yaml.Marshal(node)
+}
+
+// applyDefaults 按 default 标签设置字段默认值，递归处理嵌套结构体
+func applyDefaults(v reflect.Value, prefix string) []error {
+	var errs []error
+	walkConfigFields(v, prefix, func(field reflect.Value, tag reflect.StructTag, name string) {
+		value, ok := tag.Lookup("default")
+		if !ok {
+			return
+		}
+		if err := setFieldFromString(field, value); err != nil {
+			errs = append(errs, fmt.Errorf("xxl-job %s default %q is invalid: %w", name, value, err))
+		}
+	})
+	return errs
+}
+
+// applyEnv 按 env 标签读取环境变量，变量未设置时读取 *_FILE 变量指向的文件
+func applyEnv(v reflect.Value, lookup func(string) (string, bool)) []error {
+	var errs []error
+	walkConfigFields(v, "", func(field reflect.Value, tag reflect.StructTag, _ string) {
+		name := tag.Get("env")
+		if name == "" {
+			return
+		}
+		// 设置为空字符串的变量视为未设置
+		value, ok := lookup(name)
+		ok = ok && value != ""
+		filePath, fromFile := lookup(name + envFileSuffix)
+		fromFile = fromFile && filePath != ""
+		switch {
+		case ok && fromFile:
+			errs = append(errs, fmt.Errorf("xxl-job env %s and %s%s are both set", name, name, envFileSuffix))
+			return
+		case fromFile:
+			// #nosec G304 -- 密钥文件路径来自环境变量
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("xxl-job env %s%s: %w", name, envFileSuffix, err))
+				return
+			}
+			value = strings.TrimRight(string(data), "\r\n")
+		case !ok:
+			return
+		}
+		if err := setFieldFromString(field, value); err != nil {
+			errs = append(errs, fmt.Errorf("xxl-job env %s is invalid: %w", name, err))
+		}
+	})
+	return errs
+}
+
+// requiredFieldErrors 检查 required 标签标记的字段是否已设置
+func requiredFieldErrors(v reflect.Value, prefix string) []error {
+	var errs []error
+	walkConfigFields(v, prefix, func(field reflect.Value, tag reflect.StructTag, name string) {
+		if tag.Get("required") == "true" && field.IsZero() {
+			errs = append(errs, fmt.Errorf("xxl-job %s is required", name))
+		}
+	})
+	return errs
+}
+
+// walkConfigFields 遍历配置结构体的字段（不含结构体字段本身），name 为以 . 连接的 yaml 字段名
+func walkConfigFields(v reflect.Value, prefix string, fn func(field reflect.Value, tag reflect.StructTag, name string)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := strings.Split(sf.Tag.Get("yaml"), ",")[0]
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		if sf.Type.Kind() == reflect.Struct {
+			walkConfigFields(v.Field(i), name, fn)
+			continue
+		}
+		fn(v.Field(i), sf.Tag, name)
+	}
+}
+
+// setFieldFromString 将字符串解析为字段类型并赋值，切片按逗号分隔
+func setFieldFromString(field reflect.Value, value string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", field.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items).Convert(field.Type()))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
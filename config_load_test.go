@@ -0,0 +1,224 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// envLookup 返回从 map 读取的环境变量查询函数
+func envLookup(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
+
+// writeTestFile 在临时目录中写入文件并返回路径
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	yamlFile := writeTestFile(t, "xxl-job.yaml", `
+enabled: true
+server_addr: http://yaml:8080/xxl-job-admin
+registry_key: yaml-executor
+access_token: yaml-token
+`)
+	tokenFile := writeTestFile(t, "token", "file-token\n")
+
+	tests := []struct {
+		name      string
+		path      string
+		env       map[string]string
+		wantToken string
+		wantAddr  string
+		wantErr   string
+	}{
+		{
+			name:      "yaml",
+			path:      yamlFile,
+			wantToken: "yaml-token",
+			wantAddr:  "http://yaml:8080/xxl-job-admin",
+		},
+		{
+			name:      "env overrides yaml",
+			path:      yamlFile,
+			env:       map[string]string{"XXL_JOB_ACCESS_TOKEN": "env-token", "XXL_JOB_SERVER_ADDR": "http://env:8080/xxl-job-admin"},
+			wantToken: "env-token",
+			wantAddr:  "http://env:8080/xxl-job-admin",
+		},
+		{
+			name:      "file overrides yaml",
+			path:      yamlFile,
+			env:       map[string]string{"XXL_JOB_ACCESS_TOKEN_FILE": tokenFile},
+			wantToken: "file-token",
+			wantAddr:  "http://yaml:8080/xxl-job-admin",
+		},
+		{
+			name:      "empty env falls back to file",
+			path:      yamlFile,
+			env:       map[string]string{"XXL_JOB_ACCESS_TOKEN": "", "XXL_JOB_ACCESS_TOKEN_FILE": tokenFile},
+			wantToken: "file-token",
+			wantAddr:  "http://yaml:8080/xxl-job-admin",
+		},
+		{
+			name:      "empty file path ignored",
+			path:      yamlFile,
+			env:       map[string]string{"XXL_JOB_ACCESS_TOKEN_FILE": ""},
+			wantToken: "yaml-token",
+			wantAddr:  "http://yaml:8080/xxl-job-admin",
+		},
+		{
+			name:    "env and file both set",
+			path:    yamlFile,
+			env:     map[string]string{"XXL_JOB_ACCESS_TOKEN": "env-token", "XXL_JOB_ACCESS_TOKEN_FILE": tokenFile},
+			wantErr: "are both set",
+		},
+		{
+			name:    "missing secret file",
+			path:    yamlFile,
+			env:     map[string]string{"XXL_JOB_ACCESS_TOKEN_FILE": filepath.Join(t.TempDir(), "missing")},
+			wantErr: "XXL_JOB_ACCESS_TOKEN_FILE",
+		},
+		{
+			name: "env only",
+			env: map[string]string{
+				"XXL_JOB_ENABLED":      "true",
+				"XXL_JOB_SERVER_ADDR":  "http://env:8080/xxl-job-admin",
+				"XXL_JOB_REGISTRY_KEY": "env-executor",
+			},
+			wantAddr: "http://env:8080/xxl-job-admin",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadConfig(tt.path, WithEnvLookup(envLookup(tt.env)))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.AccessToken != tt.wantToken || cfg.ServerAddr != tt.wantAddr {
+				t.Errorf("access_token = %q, server_addr = %q; want %q, %q", cfg.AccessToken, cfg.ServerAddr, tt.wantToken, tt.wantAddr)
+			}
+			// 未配置的字段使用 default 标签
+			if cfg.ExecutorPort != "9999" || cfg.LogRetentionDays != 30 {
+				t.Errorf("defaults not applied: executor_port = %q, log_retention_days = %d", cfg.ExecutorPort, cfg.LogRetentionDays)
+			}
+		})
+	}
+}
+
+func TestLoadConfigRequired(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		wantErrs []string
+		notErr   string // 不应出现在错误中的内容
+	}{
+		{
+			name: "disabled skips required fields",
+			env:  map[string]string{},
+		},
+		{
+			name:     "enabled reports every missing field",
+			env:      map[string]string{"XXL_JOB_ENABLED": "true"},
+			wantErrs: []string{"server_addr is required", "registry_key is required"},
+		},
+		{
+			name:     "enabled with one missing field",
+			env:      map[string]string{"XXL_JOB_ENABLED": "true", "XXL_JOB_SERVER_ADDR": "http://admin"},
+			wantErrs: []string{"registry_key is required"},
+			notErr:   "server_addr",
+		},
+		{
+			name: "enabled with required fields",
+			env:  map[string]string{"XXL_JOB_ENABLED": "true", "XXL_JOB_SERVER_ADDR": "http://admin", "XXL_JOB_REGISTRY_KEY": "demo"},
+		},
+		{
+			name:     "invalid env value",
+			env:      map[string]string{"XXL_JOB_LOG_RETENTION_DAYS": "many"},
+			wantErrs: []string{"XXL_JOB_LOG_RETENTION_DAYS is invalid"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig("", WithEnvLookup(envLookup(tt.env)))
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("error = nil, want %v", tt.wantErrs)
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %v, want %q", err, want)
+				}
+			}
+			if tt.notErr != "" && strings.Contains(err.Error(), tt.notErr) {
+				t.Errorf("error = %v, should not mention %q", err, tt.notErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfigYAMLErrors(t *testing.T) {
+	path := writeTestFile(t, "xxl-job.yaml", `
+app:
+  xxl_job:
+    enabled: true
+    server_addr: http://admin
+    registry_key: demo
+    log_retention_days: many
+    unknown_field: 1
+`)
+
+	_, err := LoadConfig(path, WithEnvLookup(nil), WithConfigSection("app.xxl_job"))
+	if err == nil {
+		t.Fatal("error = nil, want YAML errors")
+	}
+	// 类型错误和未知字段合并后一次返回，读取子节点时不显示行号
+	for _, want := range []string{"unknown_field", "many"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %v, want %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "line ") {
+		t.Errorf("error = %v, should not contain line numbers", err)
+	}
+
+	if _, err := LoadConfig(path, WithEnvLookup(nil), WithConfigSection("app.missing")); err == nil {
+		t.Error("missing section: error = nil")
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-anyway/framework-log"
+	"go.uber.org/zap"
+)
+
+// defaultConfigWatchInterval 配置文件默认检查间隔
+const defaultConfigWatchInterval = 10 * time.Second
+
+// reloadableConfigFields 执行器运行期间可以修改的配置项（顶层 yaml 字段名），其余配置项需要重启执行器
+var reloadableConfigFields = map[string]bool{
+	"log_retention_days": true,
+	"log_sync_policy":    true,
+	"log_flush_interval": true,
+	"log_buffer_size":    true,
+	"log_format":         true,
+	"log_level":          true,
+	"task_log_levels":    true,
+	"redaction":          true,
+	"enable_trace":       true,
+	"trace_propagation":  true,
+	"task_traces":        true,
+	"trace_log_events":   true,
+	"admin":              true, // 执行器不使用
+	"quiet_mode":         true,
+}
+
+// ConfigChange 配置项变更
+type ConfigChange struct {
+	Field string // yaml 字段名，嵌套字段用 . 连接，例如 redaction.keys
+	Old   string // 原值，敏感字段显示为 ***
+	New   string // 新值，敏感字段显示为 ***
+}
+
+// String 返回 field: old -> new 格式的描述
+func (c ConfigChange) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Field, c.Old, c.New)
+}
+
+// ConfigApplyResult ApplyConfig 的结果
+type ConfigApplyResult struct {
+	Applied         []ConfigChange // 已生效的变更
+	RestartRequired []ConfigChange // 需要重启执行器才能生效的变更（未生效）
+}
+
+// configValue 配置项的值（格式化为字符串），用于比较配置变更
+type configValue struct {
+	field     string
+	value     string
+	sensitive bool // 敏感字段（如 access_token），变更描述中不显示原文
+}
+
+// display 返回变更描述中的显示值
+func (v configValue) display() string {
+	switch {
+	case v.value == "":
+		return `""`
+	case v.sensitive:
+		return redactedValue
+	default:
+		return v.value
+	}
+}
+
+// snapshotConfig 记录配置中所有字段的值
+func snapshotConfig(cfg *Config) []configValue {
+	redactor := defaultRedactor()
+	var values []configValue
+	walkConfigFields(reflect.ValueOf(cfg).Elem(), "", func(field reflect.Value, _ reflect.StructTag, name string) {
+		values = append(values, configValue{
+			field:     name,
+			value:     fmt.Sprint(field.Interface()),
+			sensitive: field.Kind() == reflect.String && redactor.isSensitiveKey(name[strings.LastIndex(name, ".")+1:]),
+		})
+	})
+	return values
+}
+
+// diffConfig 比较两份配置快照，返回变更的配置项
+func diffConfig(old, next []configValue) []ConfigChange {
+	var changes []ConfigChange
+	for i := range next {
+		if old[i].value != next[i].value {
+			changes = append(changes, ConfigChange{
+				Field: next[i].field,
+				Old:   old[i].display(),
+				New:   next[i].display(),
+			})
+		}
+	}
+	return changes
+}
+
+// configFromOptions 根据执行器选项推算配置，作为未从 Config 创建的执行器的比较基准
+// 自定义日志存储和脱敏规则无法从选项还原，分别按存储类型和默认规则推算
+func configFromOptions(opts *executorOptions) *Config {
+	cfg := &Config{}
+	applyDefaults(reflect.ValueOf(cfg).Elem(), "")
+
+	cfg.Enabled = true
+	cfg.ServerAddr = opts.serverAddr
+	cfg.AccessToken = opts.accessToken
+	cfg.ExecutorIP = opts.executorIP
+	cfg.ExecutorPort = opts.executorPort
+	cfg.RegistryKey = opts.registryKey
+	cfg.LogPath = opts.logPath
+	cfg.LogRetentionDays = opts.logRetentionDays
+	cfg.StateFile = opts.stateFile
+	cfg.LogSyncPolicy = opts.logSyncPolicy.String()
+	cfg.LogFlushInterval = opts.logFlushInterval
+	cfg.LogBufferSize = opts.logBufferSize
+	cfg.LogFormat = opts.logFormat.String()
+	cfg.LogLevel = strings.ToLower(opts.logLevel.String())
+	cfg.TaskLogLevels = make(map[string]string, len(opts.taskLogLevels))
+	for task, level := range opts.taskLogLevels {
+		cfg.TaskLogLevels[task] = strings.ToLower(level.String())
+	}
+	switch opts.logStore.(type) {
+	case *FileLogStore:
+		cfg.LogStore = "file"
+	case *MemoryLogStore:
+		cfg.LogStore = "memory"
+	case *S3LogStore:
+		cfg.LogStore = "s3"
+	}
+	cfg.Redaction.Disabled = opts.redactor == nil || !opts.redactor.enabled
+	cfg.EnableTrace = opts.enableTrace
+	cfg.TracePropagation = opts.tracePropagation.String()
+	cfg.TaskTraces = opts.taskTraces
+	cfg.TraceLogEvents = opts.traceLogEvents
+	cfg.History = opts.history
+	cfg.Fallback = opts.fallback
+	cfg.Concurrency = opts.concurrency
+	cfg.TaskConcurrency = opts.taskConcurrency
+	cfg.QuietMode = opts.quietMode
+	return cfg
+}
+
+// ApplyConfig 在执行器运行期间应用配置
+// 日志、追踪、脱敏和静默模式等配置立即生效（对之后开始的任务），
+// 其余配置项（调度中心地址、端口、AccessToken、日志存储、并发限制等）不生效，在结果中返回，需要重启执行器。
+// AccessToken 由 SDK 在创建时保存，用于注册和回调，无法在运行期间修改
+func (e *executorImpl) ApplyConfig(cfg *Config) (*ConfigApplyResult, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	// 禁用执行器需要重启，验证时按启用处理，保证可以立即生效的配置项合法
+	check := *cfg
+	check.Enabled = true
+	if err := check.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	e.configMu.Lock()
+	defer e.configMu.Unlock()
+
+	current := e.config
+	if current == nil {
+		current = snapshotConfig(configFromOptions(e.options()))
+	}
+	next := snapshotConfig(cfg)
+
+	result := &ConfigApplyResult{}
+	for _, change := range diffConfig(current, next) {
+		if reloadableConfigFields[strings.SplitN(change.Field, ".", 2)[0]] {
+			result.Applied = append(result.Applied, change)
+		} else {
+			result.RestartRequired = append(result.RestartRequired, change)
+		}
+	}
+
+	if len(result.Applied) > 0 {
+		opts := *e.options()
+		cfg.applyReloadable(&opts)
+		e.opts.Store(&opts)
+		sdkQuietMode.Store(opts.quietMode)
+	}
+
+	// 需要重启的配置项保留原值，之后的 ApplyConfig 仍会报告
+	for i := range next {
+		if !reloadableConfigFields[strings.SplitN(next[i].field, ".", 2)[0]] {
+			next[i] = current[i]
+		}
+	}
+	e.config = next
+
+	if len(result.Applied) > 0 {
+		log.Info("XXL-JOB config applied", zap.Strings("changes", configChangeStrings(result.Applied)))
+	}
+	if len(result.RestartRequired) > 0 {
+		log.Warn("XXL-JOB config changes require restart", zap.Strings("changes", configChangeStrings(result.RestartRequired)))
+	}
+	return result, nil
+}
+
+// configChangeStrings 返回配置变更的描述列表
+func configChangeStrings(changes []ConfigChange) []string {
+	lines := make([]string, len(changes))
+	for i, change := range changes {
+		lines[i] = change.String()
+	}
+	return lines
+}
+
+// WatchConfigFile 定期检查配置文件，内容变化时使用 LoadConfig 重新加载并调用 ApplyConfig（阻塞直到 ctx 取消）
+// 加载或应用失败时记录日志并保留当前配置；interval 小于等于 0 时使用默认值 10s。
+// 只检查文件内容，环境变量的变化不会触发重新加载
+func WatchConfigFile(ctx context.Context, exec Executor, path string, interval time.Duration, opts ...LoadOption) error {
+	if exec == nil {
+		return fmt.Errorf("executor cannot be nil")
+	}
+	if interval <= 0 {
+		interval = defaultConfigWatchInterval
+	}
+	// #nosec G304 -- 配置文件路径由调用方指定
+	last, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read xxl-job config: %w", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		// #nosec G304 -- 配置文件路径由调用方指定
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Warn("Failed to read XXL-JOB config", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		if bytes.Equal(data, last) {
+			continue
+		}
+		last = data
+
+		cfg, err := LoadConfig(path, opts...)
+		if err != nil {
+			log.Error("Failed to reload XXL-JOB config", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		if _, err := exec.ApplyConfig(cfg); err != nil {
+			log.Error("Failed to apply XXL-JOB config", zap.String("path", path), zap.Error(err))
+		}
+	}
+}
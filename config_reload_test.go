@@ -0,0 +1,170 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// reloadTestConfig 返回使用内存日志存储的启用配置
+func reloadTestConfig(t *testing.T) *Config {
+	t.Helper()
+	cfg, err := LoadConfig("", WithEnvLookup(envLookup(map[string]string{
+		"XXL_JOB_ENABLED":      "true",
+		"XXL_JOB_SERVER_ADDR":  "http://127.0.0.1:1/xxl-job-admin",
+		"XXL_JOB_REGISTRY_KEY": "test-executor",
+		"XXL_JOB_ACCESS_TOKEN": "old-token",
+		"XXL_JOB_LOG_STORE":    "memory",
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// changedFields 返回变更的字段名
+func changedFields(changes []ConfigChange) []string {
+	var fields []string
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+	return fields
+}
+
+func TestApplyConfigClassifiesChanges(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      func(cfg *Config)
+		wantApplied []string
+		wantRestart []string
+	}{
+		{
+			name:   "no change",
+			change: func(*Config) {},
+		},
+		{
+			name: "log settings reload",
+			change: func(cfg *Config) {
+				cfg.LogLevel = "debug"
+				cfg.LogFlushInterval = 5 * time.Second
+				cfg.TaskLogLevels = map[string]string{"demo": "warn"}
+			},
+			wantApplied: []string{"log_flush_interval", "log_level", "task_log_levels"},
+		},
+		{
+			name: "nested reloadable field",
+			change: func(cfg *Config) {
+				cfg.Redaction.Keys = []string{"card_no"}
+				cfg.TraceLogEvents.Disabled = true
+			},
+			wantApplied: []string{"redaction.keys", "trace_log_events.disabled"},
+		},
+		{
+			name: "connection settings require restart",
+			change: func(cfg *Config) {
+				cfg.ServerAddr = "http://127.0.0.1:2/xxl-job-admin"
+				cfg.ExecutorPort = "9998"
+				cfg.AccessToken = "new-token"
+			},
+			wantRestart: []string{"server_addr", "access_token", "executor_port"},
+		},
+		{
+			name: "nested restart field",
+			change: func(cfg *Config) {
+				cfg.Concurrency.Limit = 2
+				cfg.History.Size = 10
+			},
+			wantRestart: []string{"concurrency.limit", "history.size"},
+		},
+		{
+			name: "mixed",
+			change: func(cfg *Config) {
+				cfg.QuietMode = true
+				cfg.Enabled = false
+			},
+			wantApplied: []string{"quiet_mode"},
+			wantRestart: []string{"enabled"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec, err := NewFromConfig(reloadTestConfig(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+			next := reloadTestConfig(t)
+			tt.change(next)
+
+			result, err := exec.ApplyConfig(next)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := changedFields(result.Applied); !reflect.DeepEqual(got, tt.wantApplied) {
+				t.Errorf("Applied = %v, want %v", got, tt.wantApplied)
+			}
+			if got := changedFields(result.RestartRequired); !reflect.DeepEqual(got, tt.wantRestart) {
+				t.Errorf("RestartRequired = %v, want %v", got, tt.wantRestart)
+			}
+		})
+	}
+}
+
+func TestApplyConfigEffects(t *testing.T) {
+	exec, err := NewFromConfig(reloadTestConfig(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := exec.(*executorImpl)
+
+	next := reloadTestConfig(t)
+	next.LogLevel = "error"
+	next.AccessToken = "new-token"
+	result, err := exec.ApplyConfig(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.options().logLevel != LogLevelError {
+		t.Errorf("log level = %v, want error", e.options().logLevel)
+	}
+	if e.options().accessToken != "old-token" {
+		t.Errorf("access token changed to %q without restart", e.options().accessToken)
+	}
+	// 敏感字段不显示原文
+	if len(result.RestartRequired) != 1 || result.RestartRequired[0].Old != redactedValue || result.RestartRequired[0].New != redactedValue {
+		t.Errorf("RestartRequired = %+v, want redacted access_token", result.RestartRequired)
+	}
+
+	// 需要重启的变更未生效，之后的 ApplyConfig 仍会报告；已生效的变更不再报告
+	result, err = exec.ApplyConfig(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Applied) != 0 || !reflect.DeepEqual(changedFields(result.RestartRequired), []string{"access_token"}) {
+		t.Errorf("second ApplyConfig = %+v", result)
+	}
+
+	invalid := reloadTestConfig(t)
+	invalid.LogLevel = "verbose"
+	if _, err := exec.ApplyConfig(invalid); err == nil {
+		t.Error("invalid config applied")
+	}
+	if e.options().logLevel != LogLevelError {
+		t.Errorf("log level after invalid config = %v, want error", e.options().logLevel)
+	}
+}
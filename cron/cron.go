@@ -0,0 +1,470 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package cron 解析 Quartz 风格的 cron 表达式（与 XXL-JOB 调度中心一致）
+//
+// 表达式由 6 或 7 个字段组成，以空格分隔：
+//
+//	秒 分 时 日 月 周 [年]
+//
+// 支持 *、?、列表（a,b）、范围（a-b）、步长（a/n、a-b/n、*/n），月份和星期支持英文缩写（JAN、MON）。
+// 与 Quartz 一致，起始值大于结束值的范围跨越字段最大值回绕，例如 FRI-MON、50-10（年份字段除外）。
+// 星期字段与 Quartz 一致：1 表示周日，7 表示周六。日和周字段中必须有一个为 ?
+//
+// 日字段还支持：
+//
+//	L     当月最后一天
+//	L-n   当月倒数第 n+1 天（最后一天之前 n 天）
+//	nW    离 n 日最近的工作日（不跨月）
+//	LW    当月最后一个工作日
+//	L-nW  离当月倒数第 n+1 天最近的工作日
+//
+// 周字段还支持：
+//
+//	L     周六（同 7）
+//	nL    当月最后一个周 n，例如 6L 表示最后一个周五
+//	n#k   当月第 k 个周 n，例如 2#1 表示第一个周一
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// minYear、maxYear 年份字段的取值范围（与 Quartz 一致）
+	minYear = 1970
+	maxYear = 2099
+)
+
+// field 字段定义
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	secondField = field{name: "second", min: 0, max: 59}
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day-of-month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}}
+	dowField = field{name: "day-of-week", min: 1, max: 7, names: map[string]int{
+		"SUN": 1, "MON": 2, "TUE": 3, "WED": 4, "THU": 5, "FRI": 6, "SAT": 7,
+	}}
+	yearField = field{name: "year", min: minYear, max: maxYear}
+)
+
+// Schedule 解析后的 cron 表达式
+type Schedule struct {
+	expr string
+
+	seconds, minutes, hours uint64
+	months                  uint64
+	dom, dow                uint64 // 对应字段为 ? 或使用特殊字符时为 0
+	years                   map[int]bool
+	loc                     *time.Location // 为空时使用传入时间的时区
+
+	// 日字段特殊字符
+	domLast        bool // L、L-n
+	domLastOffset  int  // L-n 中的 n
+	domWeekday     int  // nW 中的 n
+	domLastWeekday bool // LW、L-nW（偏移量同样保存在 domLastOffset）
+
+	// 周字段特殊字符
+	dowLast   int // nL 中的 n
+	dowNth    int // n#k 中的 k
+	dowNthDay int // n#k 中的 n
+}
+
+// Parse 解析 Quartz cron 表达式
+func Parse(expr string) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 6 && len(fields) != 7 {
+		return nil, fmt.Errorf("cron: expected 6 or 7 fields, got %d: %q", len(fields), expr)
+	}
+
+	s := &Schedule{expr: expr}
+	var err error
+	if s.seconds, err = parseField(fields[0], secondField); err != nil {
+		return nil, err
+	}
+	if s.minutes, err = parseField(fields[1], minuteField); err != nil {
+		return nil, err
+	}
+	if s.hours, err = parseField(fields[2], hourField); err != nil {
+		return nil, err
+	}
+	if s.months, err = parseField(fields[4], monthField); err != nil {
+		return nil, err
+	}
+
+	domAny, dowAny := fields[3] == "?", fields[5] == "?"
+	if domAny == dowAny {
+		return nil, fmt.Errorf("cron: exactly one of day-of-month and day-of-week must be '?': %q", expr)
+	}
+	if !domAny {
+		if err = s.parseDayOfMonth(fields[3]); err != nil {
+			return nil, err
+		}
+	}
+	if !dowAny {
+		if err = s.parseDayOfWeek(fields[5]); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(fields) == 7 && fields[6] != "*" {
+		if s.years, err = parseYears(fields[6]); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// ParseInLocation 解析 Quartz cron 表达式，触发时间按 loc 时区计算
+func ParseInLocation(expr string, loc *time.Location) (*Schedule, error) {
+	s, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	s.loc = loc
+	return s, nil
+}
+
+// Validate 校验 Quartz cron 表达式，并检查当前时间之后是否还有触发时间（例如年份已过去的表达式不再触发）
+func Validate(expr string) error {
+	s, err := Parse(expr)
+	if err != nil {
+		return err
+	}
+	if s.Next(time.Now()).IsZero() {
+		return fmt.Errorf("cron: expression never fires: %q", expr)
+	}
+	return nil
+}
+
+// MustParse 解析 cron 表达式，失败时 panic
+func MustParse(expr string) *Schedule {
+	s, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// String 返回原始表达式
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// Location 返回计算触发时间使用的时区，为空时使用传入时间的时区
+func (s *Schedule) Location() *time.Location {
+	return s.loc
+}
+
+// Next 返回 t 之后（不含 t）的下一次触发时间；没有下一次触发时返回零值
+// 未指定时区时使用 t 的时区。夏令时开始时跳过的时间不触发；
+// 夏令时结束时重复的一小时内，指定了小时的表达式只在第一次经过时触发，每小时触发的表达式按实际时间触发
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.loc != nil {
+		t = t.In(s.loc)
+	}
+	t = t.Truncate(time.Second).Add(time.Second)
+	loc := t.Location()
+
+	for t.Year() <= maxYear {
+		if s.years != nil && !s.years[t.Year()] {
+			t = forward(t, time.Date(t.Year()+1, time.January, 1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if !has(s.months, int(t.Month())) {
+			t = forward(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if !s.matchDay(t) {
+			t = forward(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if !has(s.hours, t.Hour()) || (s.hours != allHours && repeatedHour(t)) {
+			t = nextHour(t)
+			continue
+		}
+		if !has(s.minutes, t.Minute()) {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+		if !has(s.seconds, t.Second()) {
+			t = t.Add(time.Second)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// allHours 小时字段为 * 时的位集合
+const allHours = 1<<24 - 1
+
+// forward 返回 next；夏令时开始时 time.Date 可能把不存在的时间换算到 t 之前，此时按实际时间前进到跳变之后
+func forward(t, next time.Time) time.Time {
+	for !next.After(t) {
+		next = next.Add(time.Hour)
+	}
+	return next
+}
+
+// nextHour 按实际时间前进到下一个整点
+// 不使用 time.Date(..., t.Hour()+1, ...)，夏令时开始时它会把不存在的整点换算回前一小时，导致无法前进
+func nextHour(t time.Time) time.Time {
+	return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second).Add(time.Hour)
+}
+
+// repeatedHour 判断 t 是否处于夏令时结束时第二次经过的一小时
+func repeatedHour(t time.Time) bool {
+	earlier := t.Add(-time.Hour)
+	return earlier.Hour() == t.Hour() && earlier.Day() == t.Day()
+}
+
+// NextN 返回 t 之后的 n 次触发时间，没有更多触发时间时提前结束
+func (s *Schedule) NextN(t time.Time, n int) []time.Time {
+	times := make([]time.Time, 0, n)
+	for len(times) < n {
+		t = s.Next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+// matchDay 判断日期是否匹配日或周字段
+func (s *Schedule) matchDay(t time.Time) bool {
+	day := t.Day()
+	lastDay := daysIn(t)
+	weekday := int(t.Weekday()) + 1
+
+	switch {
+	case s.domLast:
+		return day == lastDay-s.domLastOffset
+	case s.domLastWeekday:
+		target := lastDay - s.domLastOffset
+		return target >= 1 && day == nearestWeekday(t, target)
+	case s.domWeekday > 0:
+		return s.domWeekday <= lastDay && day == nearestWeekday(t, s.domWeekday)
+	case s.dom != 0:
+		return has(s.dom, day)
+	case s.dowLast > 0:
+		return weekday == s.dowLast && day+7 > lastDay
+	case s.dowNth > 0:
+		return weekday == s.dowNthDay && (day-1)/7+1 == s.dowNth
+	default:
+		return has(s.dow, weekday)
+	}
+}
+
+// daysIn 返回 t 所在月份的天数
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// nearestWeekday 返回 t 所在月份中离 day 日最近的工作日（不跨月）
+func nearestWeekday(t time.Time, day int) int {
+	lastDay := daysIn(t)
+	switch time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+	case time.Sunday:
+		if day == lastDay {
+			return day - 2
+		}
+		return day + 1
+	default:
+		return day
+	}
+}
+
+// parseDayOfMonth 解析日字段，支持 L、L-n、nW、LW、L-nW
+func (s *Schedule) parseDayOfMonth(expr string) error {
+	upper := strings.ToUpper(expr)
+	switch {
+	case upper == "L":
+		s.domLast = true
+	case upper == "LW":
+		s.domLastWeekday = true
+	case strings.HasPrefix(upper, "L-"):
+		offset, weekday := strings.CutSuffix(upper[2:], "W")
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 || n > 30 {
+			return fmt.Errorf("cron: invalid offset %q in day-of-month field", expr)
+		}
+		s.domLastOffset = n
+		if weekday {
+			s.domLastWeekday = true
+		} else {
+			s.domLast = true
+		}
+	case strings.HasSuffix(upper, "W"):
+		n, err := parseValue(upper[:len(upper)-1], domField)
+		if err != nil {
+			return fmt.Errorf("cron: 'W' requires a single day in day-of-month field: %q", expr)
+		}
+		s.domWeekday = n
+	default:
+		var err error
+		s.dom, err = parseField(expr, domField)
+		return err
+	}
+	return nil
+}
+
+// parseDayOfWeek 解析周字段，支持 L、nL、n#k
+func (s *Schedule) parseDayOfWeek(expr string) error {
+	upper := strings.ToUpper(expr)
+	switch {
+	case upper == "L":
+		s.dow = 1 << 7
+	case strings.HasSuffix(upper, "L"):
+		n, err := parseValue(upper[:len(upper)-1], dowField)
+		if err != nil {
+			return fmt.Errorf("cron: 'L' requires a single day in day-of-week field: %q", expr)
+		}
+		s.dowLast = n
+	case strings.Contains(upper, "#"):
+		dayExpr, nthExpr, _ := strings.Cut(upper, "#")
+		n, err := parseValue(dayExpr, dowField)
+		if err != nil {
+			return err
+		}
+		k, err := strconv.Atoi(nthExpr)
+		if err != nil || k < 1 || k > 5 {
+			return fmt.Errorf("cron: invalid nth %q in day-of-week field, must be 1-5", nthExpr)
+		}
+		s.dowNthDay, s.dowNth = n, k
+	default:
+		var err error
+		s.dow, err = parseField(expr, dowField)
+		return err
+	}
+	return nil
+}
+
+// has 判断位集合中是否包含 v
+func has(set uint64, v int) bool {
+	return set&(1<<uint(v)) != 0
+}
+
+// parseField 解析字段为位集合
+func parseField(expr string, f field) (uint64, error) {
+	var set uint64
+	err := parseList(expr, f, func(v int) {
+		set |= 1 << uint(v)
+	})
+	return set, err
+}
+
+// parseYears 解析年份字段
+func parseYears(expr string) (map[int]bool, error) {
+	years := make(map[int]bool)
+	err := parseList(expr, yearField, func(v int) {
+		years[v] = true
+	})
+	return years, err
+}
+
+// parseList 解析以逗号分隔的列表，每个匹配值调用 add
+func parseList(expr string, f field, add func(int)) error {
+	for _, part := range strings.Split(expr, ",") {
+		if err := parsePart(part, f, add); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parsePart 解析单个列表项：*、a、a-b，可带 /n 步长；a 大于 b 时跨越最大值回绕（年份除外）
+func parsePart(part string, f field, add func(int)) error {
+	rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+	step := 1
+	if hasStep {
+		n, err := strconv.Atoi(stepExpr)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("cron: invalid step %q in %s field", stepExpr, f.name)
+		}
+		step = n
+	}
+
+	var low, high int
+	switch {
+	case rangeExpr == "*":
+		low, high = f.min, f.max
+	case strings.Contains(rangeExpr, "-"):
+		lowExpr, highExpr, _ := strings.Cut(rangeExpr, "-")
+		var err error
+		if low, err = parseValue(lowExpr, f); err != nil {
+			return err
+		}
+		if high, err = parseValue(highExpr, f); err != nil {
+			return err
+		}
+		if low > high && f.name == yearField.name {
+			return fmt.Errorf("cron: invalid range %q in %s field", rangeExpr, f.name)
+		}
+	default:
+		v, err := parseValue(rangeExpr, f)
+		if err != nil {
+			return err
+		}
+		low, high = v, v
+		if hasStep {
+			// Quartz 中 a/n 表示从 a 开始每隔 n
+			high = f.max
+		}
+	}
+
+	// 按字段取值个数取模，回绕的范围从 low 经过最大值再从最小值到 high
+	size := f.max - f.min + 1
+	span := (high - low + size) % size
+	for i := 0; i <= span; i += step {
+		add(f.min + (low-f.min+i)%size)
+	}
+	return nil
+}
+
+// parseValue 解析单个数值或名称
+func parseValue(expr string, f field) (int, error) {
+	if v, ok := f.names[strings.ToUpper(expr)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("cron: invalid value %q in %s field", expr, f.name)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("cron: value %d out of range [%d, %d] in %s field", v, f.min, f.max, f.name)
+	}
+	return v, nil
+}
@@ -0,0 +1,278 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// loadLocation 加载时区，系统缺少时区数据时跳过测试
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+// nextN 在超时保护下计算触发时间，避免实现错误时测试卡住
+func nextN(t *testing.T, s *Schedule, from time.Time, n int) []time.Time {
+	t.Helper()
+	done := make(chan []time.Time, 1)
+	go func() { done <- s.NextN(from, n) }()
+	select {
+	case times := <-done:
+		return times
+	case <-time.After(5 * time.Second):
+		t.Fatalf("NextN(%s) did not return", s)
+		return nil
+	}
+}
+
+func TestNextDaylightSaving(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+	at := func(month time.Month, day, hour, min int, zone string) string {
+		offset := map[string]int{"EST": -5 * 3600, "EDT": -4 * 3600}[zone]
+		return time.Date(2026, month, day, hour, min, 0, 0, time.FixedZone(zone, offset)).Format(time.RFC3339)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want []string
+	}{
+		{
+			name: "spring forward skips missing time",
+			expr: "0 30 2 * * ?",
+			from: time.Date(2026, time.March, 7, 0, 0, 0, 0, newYork),
+			want: []string{at(time.March, 7, 2, 30, "EST"), at(time.March, 9, 2, 30, "EDT")},
+		},
+		{
+			name: "spring forward hourly",
+			expr: "0 0 * * * ?",
+			from: time.Date(2026, time.March, 8, 0, 30, 0, 0, newYork),
+			want: []string{at(time.March, 8, 1, 0, "EST"), at(time.March, 8, 3, 0, "EDT"), at(time.March, 8, 4, 0, "EDT")},
+		},
+		{
+			name: "spring forward every 20 minutes in gap hour",
+			expr: "0 */20 2 * * ?",
+			from: time.Date(2026, time.March, 8, 1, 50, 0, 0, newYork),
+			want: []string{at(time.March, 9, 2, 0, "EDT")},
+		},
+		{
+			name: "fall back fires repeated time once",
+			expr: "0 30 1 * * ?",
+			from: time.Date(2026, time.October, 31, 12, 0, 0, 0, newYork),
+			want: []string{at(time.November, 1, 1, 30, "EDT"), at(time.November, 2, 1, 30, "EST")},
+		},
+		{
+			name: "fall back minutes in repeated hour",
+			expr: "0 0/30 1 * * ?",
+			from: time.Date(2026, time.November, 1, 0, 0, 0, 0, newYork),
+			want: []string{at(time.November, 1, 1, 0, "EDT"), at(time.November, 1, 1, 30, "EDT"), at(time.November, 2, 1, 0, "EST")},
+		},
+		{
+			name: "fall back hourly follows elapsed time",
+			expr: "0 0 * * * ?",
+			from: time.Date(2026, time.November, 1, 0, 30, 0, 0, newYork),
+			want: []string{at(time.November, 1, 1, 0, "EDT"), at(time.November, 1, 1, 0, "EST"), at(time.November, 1, 2, 0, "EST")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseInLocation(tt.expr, newYork)
+			if err != nil {
+				t.Fatalf("ParseInLocation(%q): %v", tt.expr, err)
+			}
+			got := nextN(t, s, tt.from, len(tt.want))
+			if len(got) != len(tt.want) {
+				t.Fatalf("NextN = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(mustParseTime(t, tt.want[i])) {
+					t.Errorf("NextN[%d] = %s, want %s", i, got[i].Format(time.RFC3339), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestNextAlwaysAdvances 在有夏令时的时区中，触发时间必须严格递增且不会卡住
+func TestNextAlwaysAdvances(t *testing.T) {
+	zones := []string{"America/New_York", "Europe/London", "Australia/Sydney", "America/Santiago", "Australia/Lord_Howe"}
+	exprs := []string{"0 0 0 * * ?", "0 30 2 * * ?", "0 */15 * * * ?", "0 0 2 ? * SUN", "0 0 1 L * ?"}
+	for _, zone := range zones {
+		loc := loadLocation(t, zone)
+		for _, expr := range exprs {
+			s, err := ParseInLocation(expr, loc)
+			if err != nil {
+				t.Fatalf("ParseInLocation(%q): %v", expr, err)
+			}
+			prev := time.Date(2026, time.January, 1, 0, 0, 0, 0, loc)
+			for _, next := range nextN(t, s, prev, 500) {
+				if !next.After(prev) {
+					t.Fatalf("%s in %s: %s is not after %s", expr, zone, next, prev)
+				}
+				prev = next
+			}
+		}
+	}
+}
+
+func TestNextSpecialDays(t *testing.T) {
+	tests := []struct {
+		expr string
+		from string
+		want []string
+	}{
+		{"0 0 10 L * ?", "2026-02-01T00:00:00Z", []string{"2026-02-28T10:00:00Z", "2026-03-31T10:00:00Z"}},
+		{"0 0 10 L-2 * ?", "2026-02-01T00:00:00Z", []string{"2026-02-26T10:00:00Z", "2026-03-29T10:00:00Z"}},
+		{"0 0 10 15W * ?", "2026-08-01T00:00:00Z", []string{"2026-08-14T10:00:00Z", "2026-09-15T10:00:00Z"}},
+		{"0 0 10 1W * ?", "2026-08-01T00:00:00Z", []string{"2026-08-03T10:00:00Z", "2026-09-01T10:00:00Z"}},
+		{"0 0 10 LW * ?", "2026-05-01T00:00:00Z", []string{"2026-05-29T10:00:00Z", "2026-06-30T10:00:00Z"}},
+		{"0 0 10 ? * 6L", "2026-01-01T00:00:00Z", []string{"2026-01-30T10:00:00Z", "2026-02-27T10:00:00Z"}},
+		{"0 0 10 ? * 2#1", "2026-01-01T00:00:00Z", []string{"2026-01-05T10:00:00Z", "2026-02-02T10:00:00Z"}},
+		{"0 0 10 ? * MON#5", "2026-01-01T00:00:00Z", []string{"2026-03-30T10:00:00Z", "2026-06-29T10:00:00Z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			got := nextN(t, s, mustParseTime(t, tt.from), len(tt.want))
+			if len(got) != len(tt.want) {
+				t.Fatalf("NextN = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(mustParseTime(t, tt.want[i])) {
+					t.Errorf("NextN[%d] = %s, want %s", i, got[i].Format(time.RFC3339), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseRanges(t *testing.T) {
+	tests := []struct {
+		expr  string
+		f     field
+		want  []int
+		valid bool
+	}{
+		{"10-15/2", secondField, []int{10, 12, 14}, true},
+		{"50-10", secondField, []int{50, 51, 52, 53, 54, 55, 56, 57, 58, 59, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, true},
+		{"50-10/5", minuteField, []int{50, 55, 0, 5, 10}, true},
+		{"22-2", hourField, []int{22, 23, 0, 1, 2}, true},
+		{"30-2", domField, []int{30, 31, 1, 2}, true},
+		{"NOV-FEB", monthField, []int{11, 12, 1, 2}, true},
+		{"FRI-MON", dowField, []int{6, 7, 1, 2}, true},
+		{"SAT-SUN", dowField, []int{7, 1}, true},
+		{"5/20", secondField, []int{5, 25, 45}, true},
+		{"2030-2028", yearField, nil, false},
+		{"10-60", secondField, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.f.name+" "+tt.expr, func(t *testing.T) {
+			var got []int
+			err := parseList(tt.expr, tt.f, func(v int) { got = append(got, v) })
+			if (err == nil) != tt.valid {
+				t.Fatalf("parseList(%q) error = %v, want valid %v", tt.expr, err, tt.valid)
+			}
+			if !tt.valid {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseList(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("parseList(%q) = %v, want %v", tt.expr, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNextWrapAround(t *testing.T) {
+	tests := []struct {
+		expr string
+		from string
+		want []string
+	}{
+		{"0 0 10 ? * FRI-MON", "2026-01-01T00:00:00Z", []string{"2026-01-02T10:00:00Z", "2026-01-03T10:00:00Z", "2026-01-04T10:00:00Z", "2026-01-05T10:00:00Z", "2026-01-09T10:00:00Z"}},
+		{"0 0 22-1 1 * ?", "2026-01-01T00:00:00Z", []string{"2026-01-01T00:00:00Z", "2026-01-01T01:00:00Z", "2026-01-01T22:00:00Z", "2026-01-01T23:00:00Z", "2026-02-01T00:00:00Z"}},
+		{"0 0 10 L-3W * ?", "2026-05-01T00:00:00Z", []string{"2026-05-28T10:00:00Z", "2026-06-26T10:00:00Z", "2026-07-28T10:00:00Z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			// 起始时间前一秒开始计算，包含起始时间本身
+			got := nextN(t, s, mustParseTime(t, tt.from).Add(-time.Second), len(tt.want))
+			if len(got) != len(tt.want) {
+				t.Fatalf("NextN = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(mustParseTime(t, tt.want[i])) {
+					t.Errorf("NextN[%d] = %s, want %s", i, got[i].Format(time.RFC3339), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	year := time.Now().Year()
+	tests := []struct {
+		expr  string
+		valid bool
+	}{
+		{"0 0 10 * * ?", true},
+		{"0 0 10 L-3W * ?", true},
+		{"0 0 10 ? * FRI-MON", true},
+		{fmt.Sprintf("0 0 10 1 1 ? %d-%d", year+1, year+2), true},
+		// 年份已经过去的表达式不会再触发
+		{fmt.Sprintf("0 0 10 1 1 ? %d", year-1), false},
+		{"0 0 10 30 2 ?", false},
+		{"0 0 10 * * *", false},
+		{"0 0 10 L-31 * ?", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if err := Validate(tt.expr); (err == nil) != tt.valid {
+				t.Errorf("Validate(%q) error = %v, want valid %v", tt.expr, err, tt.valid)
+			}
+		})
+	}
+}
+
+// mustParseTime 解析 RFC3339 时间
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("time.Parse(%q): %v", value, err)
+	}
+	return parsed
+}
@@ -23,6 +23,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-anyway/framework-log"
@@ -33,14 +34,27 @@ import (
 
 // executorImpl 执行器实现
 type executorImpl struct {
-	executor    xxl.Executor
-	opts        *executorOptions
-	registry    *TaskRegistry
-	running     bool
-	runningMu   sync.RWMutex
-	startedAt   time.Time
-	lastError   error
-	lastErrorMu sync.RWMutex
+	executor      xxl.Executor
+	opts          atomic.Pointer[executorOptions] // ApplyConfig 时整体替换，通过 options() 读取
+	configMu      sync.Mutex                      // 串行化 ApplyConfig
+	config        []configValue                   // 当前生效的配置，用于比较 ApplyConfig 的变更
+	registry      *TaskRegistry
+	logStore      LogStore
+	logWriters    *logWriterSet
+	traceCarriers *traceCarrierStore
+	limits        *concurrencyLimits
+	pauses        *taskPauses
+	history       *executionHistory
+	registration  *registrationMonitor
+	fallback      *fallbackScheduler // 未启用时为 nil
+	regMu         sync.Mutex         // 串行化任务注册、替换和注销
+	sdkTasks      map[string]bool    // 已注册到 SDK 的任务
+	stopCh        chan struct{}
+	running       bool
+	runningMu     sync.RWMutex
+	startedAt     time.Time
+	lastError     error
+	lastErrorMu   sync.RWMutex
 }
 
 // NewExecutorWithOptions 使用选项创建新的执行器
@@ -53,11 +67,23 @@ func NewExecutorWithOptions(opts *executorOptions) (Executor, error) {
 		return nil, fmt.Errorf("invalid options: %w", err)
 	}
 
-	// 构建 XXL-JOB SDK 选项
+	// 加载本地暂停状态，状态文件损坏时拒绝启动，避免暂停的任务被意外执行
+	pauses, err := loadTaskPauses(opts.stateFile)
+	if err != nil {
+		return nil, err
+	}
+	history, err := newExecutionHistory(opts.history)
+	if err != nil {
+		return nil, err
+	}
+
+	// 构建 XXL-JOB SDK 选项（通过日志适配器观察注册结果）
+	registration := &registrationMonitor{}
 	xxlOpts := []xxl.Option{
 		xxl.ServerAddr(opts.serverAddr),
 		xxl.RegistryKey(opts.registryKey),
 		xxl.ExecutorPort(opts.executorPort),
+		xxl.SetLogger(&sdkLogger{registration: registration}),
 	}
 
 	// 可选配置
@@ -72,127 +98,328 @@ func NewExecutorWithOptions(opts *executorOptions) (Executor, error) {
 	// 创建真实的执行器
 	xxlExecutor := xxl.NewExecutor(xxlOpts...)
 
-	// 配置日志处理器（如果指定了日志路径）
-	// 注意：必须在 Init 之前设置，确保日志处理器被正确注册
-	if opts.logPath != "" {
-		// 确保日志目录存在
-		// #nosec G301 -- 日志目录需要可读权限
-		if err := os.MkdirAll(opts.logPath, 0755); err != nil {
-			log.Warn("Failed to create log directory",
+	// 选择日志存储：优先使用自定义存储，其次是 LogPath 下的文件存储，否则使用内存存储
+	logStore := opts.logStore
+	if logStore == nil && opts.logPath != "" {
+		fileStore, err := NewFileLogStore(opts.logPath)
+		if err != nil {
+			log.Warn("Failed to create log directory, falling back to in-memory log store",
 				zap.String("log_path", opts.logPath),
 				zap.Error(err),
 			)
 		} else {
-			// 设置自定义日志处理器（用于管理端查询日志）
-			// 注意：必须在 Init 之前注册，否则可能被 SDK 的默认处理器覆盖
-			xxlExecutor.LogHandler(func(req *xxl.LogReq) *xxl.LogRes {
-				return handleLogRequest(req, opts.logPath)
-			})
-
-			// 启动后台任务清理旧日志
-			if opts.logRetentionDays > 0 {
-				go func() {
-					ticker := time.NewTicker(1 * time.Hour) // 每小时清理一次
-					defer ticker.Stop()
-					for range ticker.C {
-						cleanupOldLogs(opts.logPath, opts.logRetentionDays)
-					}
-				}()
-			}
+			logStore = fileStore
 		}
 	}
+	if logStore == nil {
+		logStore = NewMemoryLogStore(0, 0)
+	}
+
+	// 设置自定义日志处理器（用于管理端查询日志）
+	// 注意：必须在 Init 之前注册，否则可能被 SDK 的默认处理器覆盖
+	xxlExecutor.LogHandler(func(req *xxl.LogReq) *xxl.LogRes {
+		return handleLogRequest(req, logStore)
+	})
 
 	// 初始化执行器（必须调用，否则 taskList 为 nil 会导致 panic）
 	xxlExecutor.Init(xxlOpts...)
 
 	// 如果启用了静默模式，设置日志拦截器
+	sdkQuietMode.Store(opts.quietMode)
 	if opts.quietMode {
-		setupLogInterceptor(true)
+		setupLogInterceptor()
 	}
 
-	return &executorImpl{
-		executor: xxlExecutor,
-		opts:     opts,
-		registry: NewTaskRegistry(),
-		running:  false,
-	}, nil
+	e := &executorImpl{
+		executor:      xxlExecutor,
+		registry:      NewTaskRegistry(),
+		logStore:      logStore,
+		logWriters:    newLogWriterSet(),
+		traceCarriers: newTraceCarrierStore(),
+		limits:        newConcurrencyLimits(opts.concurrency, opts.taskConcurrency),
+		pauses:        pauses,
+		history:       history,
+		registration:  registration,
+		sdkTasks:      make(map[string]bool),
+		running:       false,
+	}
+	e.opts.Store(opts)
+	e.fallback = newFallbackScheduler(e, opts.fallback, opts.fallbackLocker)
+
+	// 启动后台任务清理旧日志（保留天数可通过 ApplyConfig 修改，小于等于 0 时不清理）
+	go func() {
+		ticker := time.NewTicker(1 * time.Hour) // 每小时清理一次
+		defer ticker.Stop()
+		for range ticker.C {
+			if days := e.options().logRetentionDays; days > 0 {
+				cleanupOldLogs(logStore, days)
+			}
+		}
+	}()
+	return e, nil
 }
 
-// RegTask 注册任务
-func (e *executorImpl) RegTask(taskName string, handler TaskHandler) error {
-	e.runningMu.RLock()
-	if e.running {
-		e.runningMu.RUnlock()
-		return fmt.Errorf("cannot register task after executor started")
+// options 返回当前生效的执行器选项
+func (e *executorImpl) options() *executorOptions {
+	return e.opts.Load()
+}
+
+// RegTask 注册任务，执行器运行期间也可以注册
+// 中间件执行顺序：全局中间件（外层）→ 任务超时 → 任务级中间件 → 任务处理器
+func (e *executorImpl) RegTask(taskName string, handler TaskHandler, opts ...TaskOption) error {
+	if err := e.registerTask(taskName, handler, false, opts...); err != nil {
+		return fmt.Errorf("failed to register task: %w", err)
+	}
+	return nil
+}
+
+// ReplaceTask 替换已注册任务的处理器和选项
+// 正在执行的任务继续使用原处理器完成，之后的调度使用新处理器
+func (e *executorImpl) ReplaceTask(taskName string, handler TaskHandler, opts ...TaskOption) error {
+	if err := e.registerTask(taskName, handler, true, opts...); err != nil {
+		return fmt.Errorf("failed to replace task: %w", err)
+	}
+	return nil
+}
+
+// UnregisterTask 注销任务
+// 正在执行的任务继续执行完成，之后的调度返回 handler not found
+func (e *executorImpl) UnregisterTask(taskName string) error {
+	e.regMu.Lock()
+	defer e.regMu.Unlock()
+
+	info, ok := e.registry.Get(taskName)
+	if !ok {
+		return fmt.Errorf("failed to unregister task: task %s not found", taskName)
+	}
+	if err := e.registry.Unregister(taskName); err != nil {
+		return fmt.Errorf("failed to unregister task: %w", err)
+	}
+
+	// 恢复配置中的任务并发限制
+	if cfg := e.options().taskConcurrency[taskName]; info.Concurrency != cfg {
+		e.limits.setTaskLimit(taskName, cfg)
+	}
+
+	log.Info("XXL-JOB task unregistered", zap.String("task_name", taskName))
+	return nil
+}
+
+// registerTask 注册或替换任务
+func (e *executorImpl) registerTask(taskName string, handler TaskHandler, replace bool, opts ...TaskOption) error {
+	if handler == nil {
+		return fmt.Errorf("task handler cannot be nil")
+	}
+
+	// 应用任务选项和中间件链
+	info := newTaskInfo(taskName, handler, opts...)
+	if err := info.Concurrency.Validate(); err != nil {
+		return fmt.Errorf("invalid concurrency config for task %s: %w", taskName, err)
+	}
+	info.Handler = info.buildHandler(e.options().middlewares)
+
+	// 任务级并发限制：未在选项中设置时使用配置中的 TaskConcurrency（在加入注册表之前确定，注册后不再修改）
+	cfg := e.options().taskConcurrency[taskName]
+	if !info.concurrencySet {
+		info.Concurrency = cfg
 	}
-	e.runningMu.RUnlock()
 
-	// 应用中间件链
-	wrappedHandler := applyMiddlewares(handler, e.opts.middlewares)
+	e.regMu.Lock()
+	defer e.regMu.Unlock()
 
 	// 注册到任务注册表
-	if err := e.registry.Register(taskName, wrappedHandler); err != nil {
-		return fmt.Errorf("failed to register task: %w", err)
+	previous, exists := e.registry.Get(taskName)
+	var err error
+	if replace {
+		err = e.registry.Replace(info)
+	} else {
+		err = e.registry.Add(info)
+	}
+	if err != nil {
+		return err
 	}
 
-	// 注册到真实执行器
-	// SDK 的 TaskFunc 返回 string，我们需要将 error 转换为 string
-	e.executor.RegTask(taskName, func(ctx context.Context, param *xxl.RunReq) string {
-		// 提取参数
-		paramStr := ""
-		logID := int64(0)
-		if param != nil {
-			if param.ExecutorParams != "" {
-				paramStr = param.ExecutorParams
-			}
-			logID = param.LogID
+	// 并发限制变化时才替换限制器，替换任务但限制不变时正在执行和排队的调度继续共用原限制器
+	current := cfg
+	if exists {
+		current = previous.Concurrency
+	}
+	if info.Concurrency != current {
+		e.limits.setTaskLimit(taskName, info.Concurrency)
+	}
+	log.Debug("XXL-JOB task middlewares",
+		zap.String("task_name", taskName),
+		zap.Strings("middlewares", info.EffectiveMiddlewares),
+	)
+
+	// 注册到真实执行器（SDK 的注册表不支持删除，注销后由 runTask 返回 handler not found）
+	if !exists && !e.sdkTasks[taskName] {
+		e.sdkTasks[taskName] = true
+		e.executor.RegTask(taskName, func(ctx context.Context, param *xxl.RunReq) string {
+			return e.runTask(ctx, taskName, param, e.logStore)
+		})
+	}
+
+	if e.IsRunning() {
+		action := "registered"
+		if replace {
+			action = "replaced"
+		}
+		log.Info("XXL-JOB task "+action, zap.String("task_name", taskName))
+	}
+	return nil
+}
+
+// runTask 执行任务，任务日志写入 logStore；SDK 的 TaskFunc 返回 string，需要将 error 转换为 string
+func (e *executorImpl) runTask(ctx context.Context, taskName string, param *xxl.RunReq, logStore LogStore) (result string) {
+	// handleRun 为调度请求占用的并发槽位，任务结束（包括提前返回）时释放
+	var release func()
+	if param != nil && param.LogID > 0 {
+		release = e.limits.take(param.LogID)
+	}
+	defer func() {
+		if release != nil {
+			release()
+		}
+	}()
+
+	// 执行开始时获取当前处理器，正在执行的任务不受之后的替换、注销影响
+	info, ok := e.registry.Get(taskName)
+	if !ok {
+		log.Warn("XXL-JOB task handler not found", zap.String("task_name", taskName))
+		return fmt.Sprintf("FAIL: %s", handlerNotFoundMsg(taskName))
+	}
+
+	// 提取参数
+	paramStr := ""
+	logID := int64(0)
+	run := taskRun{taskName: taskName}
+	if param != nil {
+		if param.ExecutorParams != "" {
+			paramStr = param.ExecutorParams
+		}
+		logID = param.LogID
+		run.jobID = param.JobID
+		run.shardIndex = param.BroadcastIndex
+		run.shardTotal = param.BroadcastTotal
+	}
+	run.param, run.logID = paramStr, logID
+
+	// 记录执行历史（包括暂停、拒绝等未实际执行的调度）
+	start := time.Now()
+	var status ExecutionStatus
+	defer func() {
+		// 任务 panic 时同样记录执行历史，再继续 panic 交给调用方处理（SDK 或 runTaskRecover）
+		if r := recover(); r != nil {
+			log.Error("XXL-JOB task panic",
+				zap.String("task_name", taskName),
+				zap.Int64("log_id", logID),
+				zap.Any("panic", r),
+				zap.Stack("stack"),
+			)
+			e.history.add(newExecutionRecord(run, start, ExecutionFailed, panicResult(r)))
+			panic(r)
 		}
+		e.history.add(newExecutionRecord(run, start, status, result))
+	}()
+
+	// 创建日志写入器并注入到 context
+	if logID > 0 {
+		logWriter, logErr := newLogWriter(logStore, logID, e.logWriterOptions(taskName))
+		if logErr == nil {
+			// 将日志写入器注入到 context
+			ctx = context.WithValue(ctx, logWriterKey, logWriter)
+			e.logWriters.Add(logWriter)
+			// 确保任务执行完成后关闭日志文件（关闭时会刷新缓冲区）
+			defer func() {
+				e.logWriters.Remove(logWriter)
+				if closeErr := logWriter.Close(); closeErr != nil {
+					log.Warn("Failed to close log writer",
+						zap.Int64("log_id", logID),
+						zap.Error(closeErr),
+					)
+				}
+			}()
+		} else {
+			// 日志写入器创建失败，记录警告但不影响任务执行
+			log.Warn("Failed to create log writer",
+				zap.Int64("log_id", logID),
+				zap.Error(logErr),
+			)
+		}
+	}
 
-		// 如果配置了日志路径，创建日志写入器并注入到 context
-		if e.opts.logPath != "" && logID > 0 {
-			logWriter, logErr := newLogWriter(e.opts.logPath, logID)
-			if logErr == nil {
-				// 将日志写入器注入到 context
-				ctx = context.WithValue(ctx, logWriterKey, logWriter)
-				// 确保任务执行完成后关闭日志文件
-				defer func() {
-					if closeErr := logWriter.Close(); closeErr != nil {
-						log.Warn("Failed to close log writer",
-							zap.Int64("log_id", logID),
-							zap.Error(closeErr),
-						)
-					}
-				}()
-			} else {
-				// 日志写入器创建失败，记录警告但不影响任务执行
-				log.Warn("Failed to create log writer",
-					zap.Int64("log_id", logID),
-					zap.Error(logErr),
-				)
+	// 本地暂停的任务直接返回失败（调度中心的请求已在 handleRun 中拒绝，这里处理兜底调度、RunLocal 和排队期间暂停的任务）
+	if paused, ok := e.pauses.get(taskName); ok {
+		status = ExecutionPaused
+		return e.pausedResult(ctx, taskName, logID, paused)
+	}
+
+	// 并发限制：调度中心的请求已在 handleRun 中占用槽位，这里处理兜底调度、RunLocal 等其他来源，达到上限时按配置等待或拒绝
+	if release == nil {
+		var limitErr error
+		if release, limitErr = e.limits.acquire(ctx, taskName); limitErr != nil {
+			if logWriter := JobLogFromContext(ctx); logWriter != nil {
+				logWriter.Warn("Task rejected by concurrency limit", "error", limitErr)
 			}
+			log.Warn("XXL-JOB task rejected by concurrency limit",
+				zap.String("task_name", taskName),
+				zap.Int64("log_id", logID),
+				zap.Error(limitErr),
+			)
+			status = ExecutionRejected
+			return fmt.Sprintf("FAIL: %v", limitErr)
 		}
+	}
 
-		// 使用追踪包装器执行任务（统一日志收集、追踪、Metrics）
-		result, err := executeTaskWithTrace(
-			ctx,
-			taskName,
-			paramStr,
-			logID,
-			wrappedHandler,
-			e.opts.enableTrace,
-		)
+	// 关联上游追踪上下文（请求头或参数保留字段，参数优先）
+	carrier := mergeTraceCarriers(e.traceCarriers.Take(logID), traceCarrierFromParam(paramStr))
+	ctx, spanOpts := extractTraceContext(ctx, carrier, e.options().tracePropagation)
+
+	// 注入调度信息和任务 Logger，使 LoggerFromContext 的输出同时写入任务日志
+	ctx = contextWithRunInfo(ctx, run)
+	ctx = contextWithJobLogger(ctx, taskName, logID, JobLogFromContext(ctx))
+
+	// 使用追踪包装器执行任务（统一日志收集、追踪、Metrics）
+	result, err := executeTaskWithTrace(
+		ctx,
+		run,
+		info.Handler,
+		e.taskTraceConfig(taskName),
+		e.options().redactor,
+		e.options().traceLogEvents,
+		spanOpts...,
+	)
 
-		// 记录错误（用于健康检查）
-		if err != nil {
-			e.lastErrorMu.Lock()
-			e.lastError = err
-			e.lastErrorMu.Unlock()
+	// 记录错误（用于健康检查）
+	if err != nil {
+		e.lastErrorMu.Lock()
+		e.lastError = err
+		e.lastErrorMu.Unlock()
+	}
+
+	return result
+}
+
+// runTaskRecover 执行任务并将 panic 转换为失败结果，用于不经过 SDK 的执行来源（兜底调度、RunLocal）；
+// 调度中心触发时由 SDK 恢复 panic 并以失败状态码回调
+func (e *executorImpl) runTaskRecover(ctx context.Context, taskName string, param *xxl.RunReq, logStore LogStore) (result string) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = panicResult(r)
 		}
+	}()
+	return e.runTask(ctx, taskName, param, logStore)
+}
 
-		return result
-	})
+// panicResult 任务 panic 时的执行结果
+func panicResult(r interface{}) string {
+	return fmt.Sprintf("FAIL: task panic: %v", r)
+}
 
-	return nil
+// handlerNotFoundMsg 任务未注册时返回给调度中心的消息
+func handlerNotFoundMsg(taskName string) string {
+	return fmt.Sprintf("handler not found: %s", taskName)
 }
 
 // Run 启动执行器
@@ -204,16 +431,18 @@ func (e *executorImpl) Run() error {
 	}
 	e.running = true
 	e.startedAt = time.Now()
+	stopCh := make(chan struct{})
+	e.stopCh = stopCh
 	e.runningMu.Unlock()
 
 	// 输出启动信息
 	log.Info("XXL-JOB executor registered and started",
-		zap.String("server_addr", e.opts.serverAddr),
-		zap.String("registry_key", e.opts.registryKey),
-		zap.String("executor_port", e.opts.executorPort),
-		zap.String("executor_ip", e.opts.executorIP),
+		zap.String("server_addr", e.options().serverAddr),
+		zap.String("registry_key", e.options().registryKey),
+		zap.String("executor_port", e.options().executorPort),
+		zap.String("executor_ip", e.options().executorIP),
 		zap.Int("task_count", e.registry.Count()),
-		zap.Bool("trace_enabled", e.opts.enableTrace),
+		zap.Bool("trace_enabled", e.options().enableTrace),
 	)
 
 	// 输出已注册的任务列表
@@ -221,12 +450,21 @@ func (e *executorImpl) Run() error {
 	if len(taskNames) > 0 {
 		log.Info("XXL-JOB tasks registered",
 			zap.Strings("task_names", taskNames),
-			zap.String("registry_key", e.opts.registryKey),
+			zap.String("registry_key", e.options().registryKey),
 		)
 	}
 
-	// 启动真实执行器（会阻塞）
-	return e.executor.Run()
+	// 启动本地兜底调度（调度中心不可达时生效）
+	if e.fallback != nil {
+		go e.fallback.run(stopCh)
+	}
+
+	// 启动执行器 HTTP 服务（会阻塞）
+	err := e.serve(stopCh)
+
+	// 执行器退出时刷新尚未落盘的任务日志
+	e.logWriters.FlushAll()
+	return err
 }
 
 // Stop 停止执行器
@@ -240,9 +478,16 @@ func (e *executorImpl) Stop() error {
 
 	log.Info("Stopping XXL-JOB executor")
 	e.running = false
+	if e.stopCh != nil {
+		close(e.stopCh)
+		e.stopCh = nil
+	}
 
 	// 调用 SDK 的 Stop 方法
 	e.executor.Stop()
+
+	// 刷新尚未落盘的任务日志
+	e.logWriters.FlushAll()
 	return nil
 }
 
@@ -253,11 +498,39 @@ func (e *executorImpl) IsRunning() bool {
 	return e.running
 }
 
+// taskTraceConfig 返回任务的追踪配置，未单独配置时根据 enableTrace 全量追踪或不追踪
+func (e *executorImpl) taskTraceConfig(taskName string) TaskTraceConfig {
+	if cfg, ok := e.options().taskTraces[taskName]; ok {
+		return cfg
+	}
+	return TaskTraceConfig{Disabled: !e.options().enableTrace}
+}
+
+// GetTaskInfo 获取已注册任务的信息
+func (e *executorImpl) GetTaskInfo(taskName string) (*TaskInfo, bool) {
+	return e.registry.Get(taskName)
+}
+
 // GetTaskNames 获取所有已注册的任务名称
 func (e *executorImpl) GetTaskNames() []string {
 	return e.registry.GetNames()
 }
 
+// logWriterOptions 构建指定任务的日志写入器配置
+func (e *executorImpl) logWriterOptions(taskName string) logWriterOptions {
+	level := e.options().logLevel
+	if taskLevel, ok := e.options().taskLogLevels[taskName]; ok {
+		level = taskLevel
+	}
+	return logWriterOptions{
+		syncPolicy:    e.options().logSyncPolicy,
+		flushInterval: e.options().logFlushInterval,
+		bufferSize:    e.options().logBufferSize,
+		format:        e.options().logFormat,
+		level:         level,
+	}
+}
+
 // GetHealthStatus 获取健康状态（内部方法）
 func (e *executorImpl) GetHealthStatus() *HealthStatus {
 	e.runningMu.RLock()
@@ -270,29 +543,35 @@ func (e *executorImpl) GetHealthStatus() *HealthStatus {
 	e.lastErrorMu.RUnlock()
 
 	return &HealthStatus{
-		Running:   running,
-		TaskCount: e.registry.Count(),
-		StartedAt: startedAt,
-		LastError: lastError,
+		Running:         running,
+		TaskCount:       e.registry.Count(),
+		StartedAt:       startedAt,
+		LastError:       lastError,
+		RunningTasks:    e.limits.Running(),
+		CircuitBreakers: circuitBreakerStates(),
+		PausedTasks:     e.pauses.all(),
+		RegisteredAt:    e.registration.lastRegistered(),
+		FallbackActive:  e.fallback.isActive(),
 	}
 }
 
 // logInterceptor 日志拦截器，用于拦截和过滤 SDK 的输出
 type logInterceptor struct {
 	originalStdout *os.File
-	quietMode      bool
 	mu             sync.Mutex
 }
 
 var (
 	logInterceptorOnce sync.Once
 	logInterceptorInst *logInterceptor
+	// sdkQuietMode 静默模式开关，SDK 日志是进程级的，开关也是进程级的（可通过 ApplyConfig 修改）
+	sdkQuietMode atomic.Bool
 )
 
 // setupLogInterceptor 设置日志拦截器（仅设置一次）
 // 注意：拦截标准输出会影响全局，但这是统一日志输出的必要方案
-// 在静默模式下，会过滤掉心跳/注册成功的日志
-func setupLogInterceptor(quietMode bool) {
+// 在静默模式下（sdkQuietMode），会过滤掉心跳/注册成功的日志
+func setupLogInterceptor() {
 	logInterceptorOnce.Do(func() {
 		// 保存原始标准输出
 		originalStdout := os.Stdout
@@ -309,7 +588,6 @@ func setupLogInterceptor(quietMode bool) {
 
 		interceptor := &logInterceptor{
 			originalStdout: originalStdout,
-			quietMode:      quietMode,
 		}
 		logInterceptorInst = interceptor
 
@@ -337,7 +615,7 @@ func (li *logInterceptor) interceptLogs(r *os.File, originalStdout *os.File) {
 		line := scanner.Text()
 
 		// 在静默模式下，过滤掉心跳/注册成功的日志
-		if li.quietMode {
+		if sdkQuietMode.Load() {
 			if shouldFilterHeartbeatLog(line) {
 				// 静默模式下，不输出心跳日志
 				continue
@@ -0,0 +1,125 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	xxl "github.com/xxl-job/xxl-job-executor-go"
+)
+
+// newTestExecutor 创建不连接调度中心的执行器（SDK 回调调度中心会失败，只输出日志）
+func newTestExecutor(t *testing.T, opts ...Option) *executorImpl {
+	t.Helper()
+	o := NewOptions()
+	o.serverAddr = "http://127.0.0.1:1/xxl-job-admin"
+	o.registryKey = "test-executor"
+	o.logStore = NewMemoryLogStore(0, 0)
+	for _, opt := range opts {
+		opt(o)
+	}
+	exec, err := NewExecutorWithOptions(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return exec.(*executorImpl)
+}
+
+// postAdmin 以调度中心的身份调用执行器接口
+func postAdmin(t *testing.T, handler http.HandlerFunc, body interface{}) adminReturn {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(data)))
+
+	// SDK 拒绝调度请求时返回回调格式的数组
+	var calls []struct {
+		HandleCode int    `json:"handleCode"`
+		HandleMsg  string `json:"handleMsg"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &calls); err == nil && len(calls) == 1 {
+		return adminReturn{Code: calls[0].HandleCode, Msg: calls[0].HandleMsg}
+	}
+
+	var ret adminReturn
+	if err := json.Unmarshal(recorder.Body.Bytes(), &ret); err != nil {
+		t.Fatalf("invalid response %q: %v", recorder.Body.String(), err)
+	}
+	return ret
+}
+
+// callbackAdmin 模拟调度中心，返回指向它的地址选项和 SDK 回调的日志 ID。
+// SDK 先从运行列表摘除任务再读取任务参数回调，等到回调到达才能安全地再次调度同一处理器
+func callbackAdmin(t *testing.T) (Option, <-chan int64) {
+	t.Helper()
+	logIDs := make(chan int64, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/callback" {
+			var calls []struct {
+				LogID int64 `json:"logId"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&calls); err == nil {
+				for _, call := range calls {
+					logIDs <- call.LogID
+				}
+			}
+		}
+		_, _ = w.Write([]byte(`{"code":200}`))
+	}))
+	t.Cleanup(srv.Close)
+	return WithServerAddr(srv.URL), logIDs
+}
+
+// runRequest 构造调度请求
+func runRequest(taskName string, jobID, logID int64) xxl.RunReq {
+	return xxl.RunReq{
+		JobID:                 jobID,
+		ExecutorHandler:       taskName,
+		ExecutorBlockStrategy: "SERIAL_EXECUTION",
+		LogID:                 logID,
+	}
+}
+
+// waitFor 等待条件成立，超时后测试失败
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// historyRecord 查找指定日志 ID 的执行记录
+func historyRecord(e *executorImpl, taskName string, logID int64) (ExecutionRecord, bool) {
+	for _, record := range e.ExecutionHistory(taskName, HistoryFilter{}) {
+		if record.LogID == logID {
+			return record, true
+		}
+	}
+	return ExecutionRecord{}, false
+}
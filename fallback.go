@@ -0,0 +1,240 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-anyway/framework-log"
+	"github.com/go-anyway/framework-xxljob/cron"
+
+	xxl "github.com/xxl-job/xxl-job-executor-go"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultFallbackAfter 注册连续失败多久后启用本地兜底调度
+	defaultFallbackAfter = 5 * time.Minute
+	// defaultFallbackLockTTL 兜底调度锁的默认持有时间
+	defaultFallbackLockTTL = time.Minute
+	// fallbackTickInterval 兜底调度检查间隔
+	fallbackTickInterval = time.Second
+)
+
+// FallbackTaskConfig 本地兜底调度的任务
+type FallbackTaskConfig struct {
+	Task     string `yaml:"task"`      // 任务名称
+	Cron     string `yaml:"cron"`      // Quartz cron 表达式
+	TimeZone string `yaml:"time_zone"` // cron 使用的时区（如 Asia/Shanghai），为空时使用本地时区
+	Param    string `yaml:"param"`     // 任务参数
+}
+
+// schedule 解析任务的 cron 表达式
+func (c FallbackTaskConfig) schedule() (*cron.Schedule, error) {
+	loc := time.Local
+	if c.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(c.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid time_zone: %w", err)
+		}
+	}
+	if err := cron.Validate(c.Cron); err != nil {
+		return nil, err
+	}
+	return cron.ParseInLocation(c.Cron, loc)
+}
+
+// FallbackConfig 本地兜底调度配置
+// 启用后，执行器向调度中心注册连续失败超过 After 时，按本地声明的 cron 触发指定任务；
+// 注册恢复后自动停止兜底调度。多副本部署时需要通过 WithFallbackLocker 设置分布式锁，避免重复执行
+type FallbackConfig struct {
+	Enabled bool                 `yaml:"enabled" env:"XXL_JOB_FALLBACK_ENABLED" default:"false"`
+	After   time.Duration        `yaml:"after" env:"XXL_JOB_FALLBACK_AFTER" default:"5m"`       // 注册连续失败多久后启用，0 表示使用默认值
+	LockTTL time.Duration        `yaml:"lock_ttl" env:"XXL_JOB_FALLBACK_LOCK_TTL" default:"1m"` // 每次触发的锁持有时间，应大于副本间的时钟偏差
+	Tasks   []FallbackTaskConfig `yaml:"tasks"`
+}
+
+// DefaultFallbackConfig 返回默认兜底调度配置（未启用）
+func DefaultFallbackConfig() FallbackConfig {
+	return FallbackConfig{
+		After:   defaultFallbackAfter,
+		LockTTL: defaultFallbackLockTTL,
+	}
+}
+
+// Validate 验证兜底调度配置
+func (c FallbackConfig) Validate() error {
+	if c.After < 0 {
+		return fmt.Errorf("after must be >= 0")
+	}
+	if c.LockTTL < 0 {
+		return fmt.Errorf("lock_ttl must be >= 0")
+	}
+	for i, task := range c.Tasks {
+		if task.Task == "" {
+			return fmt.Errorf("tasks[%d]: task is required", i)
+		}
+		if _, err := task.schedule(); err != nil {
+			return fmt.Errorf("tasks[%d] (%s): %w", i, task.Task, err)
+		}
+	}
+	return nil
+}
+
+// fallbackEntry 兜底调度的任务及下一次触发时间
+type fallbackEntry struct {
+	cfg      FallbackTaskConfig
+	schedule *cron.Schedule
+	next     time.Time
+}
+
+// fallbackScheduler 本地兜底调度器
+type fallbackScheduler struct {
+	e       *executorImpl
+	after   time.Duration
+	lockTTL time.Duration
+	locker  Locker
+	entries []*fallbackEntry
+	active  atomic.Bool
+}
+
+// newFallbackScheduler 创建兜底调度器，未启用或没有任务时返回 nil
+func newFallbackScheduler(e *executorImpl, cfg FallbackConfig, locker Locker) *fallbackScheduler {
+	if !cfg.Enabled || len(cfg.Tasks) == 0 {
+		return nil
+	}
+
+	s := &fallbackScheduler{
+		e:       e,
+		after:   cfg.After,
+		lockTTL: cfg.LockTTL,
+		locker:  locker,
+	}
+	if s.after <= 0 {
+		s.after = defaultFallbackAfter
+	}
+	if s.lockTTL <= 0 {
+		s.lockTTL = defaultFallbackLockTTL
+	}
+	for _, task := range cfg.Tasks {
+		// 配置已在 Validate 中校验
+		schedule, _ := task.schedule()
+		s.entries = append(s.entries, &fallbackEntry{cfg: task, schedule: schedule})
+	}
+	return s
+}
+
+// run 运行兜底调度器，直到 stopCh 关闭
+func (s *fallbackScheduler) run(stopCh <-chan struct{}) {
+	if s.locker == nil {
+		log.Warn("XXL-JOB fallback scheduler has no locker, tasks may run on every replica")
+	}
+
+	now := time.Now()
+	for _, entry := range s.entries {
+		entry.next = entry.schedule.Next(now)
+	}
+
+	ticker := time.NewTicker(fallbackTickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case now := <-ticker.C:
+			s.tick(now)
+		}
+	}
+}
+
+// tick 更新兜底状态并触发到期的任务
+func (s *fallbackScheduler) tick(now time.Time) {
+	failing := s.e.registration.failingFor()
+	active := failing >= s.after
+	if s.active.Swap(active) != active {
+		if active {
+			log.Warn("XXL-JOB admin unreachable, fallback scheduler activated",
+				zap.Duration("failing_for", failing),
+				zap.Int("task_count", len(s.entries)),
+			)
+		} else {
+			log.Info("XXL-JOB admin reachable again, fallback scheduler stepped down")
+		}
+	}
+
+	for _, entry := range s.entries {
+		if entry.next.IsZero() || entry.next.After(now) {
+			continue
+		}
+		if active {
+			go s.fire(entry.cfg, entry.next)
+		}
+		// 错过的触发（如进程挂起）只补一次
+		entry.next = entry.schedule.Next(now)
+	}
+}
+
+// fire 触发一次任务，与调度中心触发走相同的执行路径
+func (s *fallbackScheduler) fire(task FallbackTaskConfig, fireTime time.Time) {
+	ctx := context.Background()
+
+	// 按任务和触发时间加锁，只有一个副本执行；锁保持到 TTL 到期，避免时钟偏差导致其他副本重复执行
+	if s.locker != nil {
+		key := fmt.Sprintf("xxljob:fallback:%s:%d", task.Task, fireTime.Unix())
+		lease, err := s.locker.Acquire(ctx, key, s.lockTTL)
+		if errors.Is(err, ErrLockHeld) {
+			log.Debug("XXL-JOB fallback trigger skipped: lock held by another replica",
+				zap.String("task_name", task.Task),
+				zap.Time("fire_time", fireTime),
+			)
+			return
+		}
+		if err != nil {
+			log.Warn("XXL-JOB fallback trigger skipped: failed to acquire lock",
+				zap.String("task_name", task.Task),
+				zap.Time("fire_time", fireTime),
+				zap.Error(err),
+			)
+			return
+		}
+		time.AfterFunc(s.lockTTL, func() {
+			_ = lease.Release(context.Background())
+		})
+	}
+
+	log.Info("XXL-JOB fallback trigger",
+		zap.String("task_name", task.Task),
+		zap.Time("fire_time", fireTime),
+	)
+	result := s.e.runTaskRecover(ctx, task.Task, &xxl.RunReq{
+		ExecutorHandler: task.Task,
+		ExecutorParams:  task.Param,
+	}, s.e.logStore)
+	log.Info("XXL-JOB fallback trigger finished",
+		zap.String("task_name", task.Task),
+		zap.String("result", result),
+	)
+}
+
+// isActive 判断兜底调度是否正在生效
+func (s *fallbackScheduler) isActive() bool {
+	return s != nil && s.active.Load()
+}
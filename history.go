@@ -0,0 +1,334 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-anyway/framework-log"
+	"go.uber.org/zap"
+)
+
+// defaultHistorySize 每个任务默认保留的执行记录数量
+const defaultHistorySize = 100
+
+// ExecutionStatus 执行结果状态
+type ExecutionStatus string
+
+const (
+	// ExecutionSuccess 执行成功
+	ExecutionSuccess ExecutionStatus = "success"
+	// ExecutionFailed 执行失败
+	ExecutionFailed ExecutionStatus = "failed"
+	// ExecutionCircuitOpen 熔断打开，未执行
+	ExecutionCircuitOpen ExecutionStatus = "circuit_open"
+	// ExecutionRejected 达到并发上限，未执行
+	ExecutionRejected ExecutionStatus = "rejected"
+	// ExecutionPaused 任务在本执行器上暂停，未执行
+	ExecutionPaused ExecutionStatus = "paused"
+)
+
+// ExecutionRecord 单次执行记录
+type ExecutionRecord struct {
+	TaskName   string          `json:"task_name"`
+	LogID      int64           `json:"log_id"`
+	JobID      int64           `json:"job_id"`
+	ShardIndex int64           `json:"shard_index"`
+	ShardTotal int64           `json:"shard_total"`
+	StartTime  time.Time       `json:"start_time"`
+	EndTime    time.Time       `json:"end_time"`
+	Duration   time.Duration   `json:"duration"`
+	Status     ExecutionStatus `json:"status"`
+	Message    string          `json:"message"`         // 返回给调度中心的结果
+	Error      string          `json:"error,omitempty"` // 失败原因
+}
+
+// HistoryConfig 执行历史配置
+// 零值即启用：每个任务在内存中保留最近 Size 条记录；配置 File 时记录同时追加到文件，重启后恢复
+type HistoryConfig struct {
+	Disabled bool   `yaml:"disabled" env:"XXL_JOB_HISTORY_DISABLED" default:"false"`
+	Size     int    `yaml:"size" env:"XXL_JOB_HISTORY_SIZE" default:"100"` // 每个任务保留的记录数，0 表示使用默认值
+	File     string `yaml:"file" env:"XXL_JOB_HISTORY_FILE"`               // JSON Lines 文件，为空时不持久化
+}
+
+// DefaultHistoryConfig 返回默认执行历史配置
+func DefaultHistoryConfig() HistoryConfig {
+	return HistoryConfig{
+		Size: defaultHistorySize,
+	}
+}
+
+// Validate 验证执行历史配置
+func (c HistoryConfig) Validate() error {
+	if c.Size < 0 {
+		return fmt.Errorf("size must be >= 0")
+	}
+	return nil
+}
+
+// HistoryFilter 执行历史查询条件，零值字段不参与过滤
+type HistoryFilter struct {
+	Status ExecutionStatus // 结果状态
+	LogID  int64           // 日志 ID
+	JobID  int64           // 调度中心任务 ID
+	Since  time.Time       // 开始时间不早于
+	Until  time.Time       // 开始时间早于
+	Limit  int             // 最多返回的记录数，0 表示不限制
+}
+
+// match 判断记录是否满足查询条件
+func (f HistoryFilter) match(r *ExecutionRecord) bool {
+	return (f.Status == "" || r.Status == f.Status) &&
+		(f.LogID == 0 || r.LogID == f.LogID) &&
+		(f.JobID == 0 || r.JobID == f.JobID) &&
+		(f.Since.IsZero() || !r.StartTime.Before(f.Since)) &&
+		(f.Until.IsZero() || r.StartTime.Before(f.Until))
+}
+
+// executionHistory 按任务保存的有界执行历史
+type executionHistory struct {
+	size int
+	file string
+
+	mu       sync.RWMutex
+	tasks    map[string][]ExecutionRecord // 按开始时间升序，超出 size 时丢弃最早的记录
+	appended int                          // 上次压缩后追加到文件的记录数
+}
+
+// newExecutionHistory 创建执行历史，配置了文件时加载已有记录，禁用时返回 nil
+func newExecutionHistory(cfg HistoryConfig) (*executionHistory, error) {
+	if cfg.Disabled {
+		return nil, nil
+	}
+	h := &executionHistory{
+		size:  cfg.Size,
+		file:  cfg.File,
+		tasks: make(map[string][]ExecutionRecord),
+	}
+	if h.size <= 0 {
+		h.size = defaultHistorySize
+	}
+	if h.file == "" {
+		return h, nil
+	}
+
+	if err := h.load(); err != nil {
+		return nil, err
+	}
+	// 加载后压缩文件，只保留每个任务最近的记录
+	if err := h.compactLocked(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// load 从文件加载执行记录，忽略无法解析的行（如写入中途崩溃留下的半行）
+func (h *executionHistory) load() error {
+	// #nosec G304 -- 文件路径来自配置
+	file, err := os.Open(h.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record ExecutionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || record.TaskName == "" {
+			continue
+		}
+		h.appendLocked(record)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read history file: %w", err)
+	}
+	return nil
+}
+
+// add 添加执行记录
+func (h *executionHistory) add(record ExecutionRecord) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.appendLocked(record)
+	if h.file == "" {
+		return
+	}
+
+	// 文件中的记录明显多于内存中保留的记录时压缩，否则追加
+	var err error
+	if h.appended++; h.appended > h.size*len(h.tasks) {
+		err = h.compactLocked()
+	} else {
+		err = h.writeLine(record)
+	}
+	if err != nil {
+		log.Warn("Failed to persist execution history",
+			zap.String("task_name", record.TaskName),
+			zap.String("file", h.file),
+			zap.Error(err),
+		)
+	}
+}
+
+// appendLocked 添加记录到内存，调用方需持有 mu
+func (h *executionHistory) appendLocked(record ExecutionRecord) {
+	records := append(h.tasks[record.TaskName], record)
+	if over := len(records) - h.size; over > 0 {
+		records = append([]ExecutionRecord(nil), records[over:]...)
+	}
+	h.tasks[record.TaskName] = records
+}
+
+// writeLine 追加一条记录到文件
+func (h *executionHistory) writeLine(record ExecutionRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	// #nosec G302,G304 -- 文件路径来自配置
+	file, err := os.OpenFile(h.file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// compactLocked 用内存中的记录重写文件（先写临时文件再重命名），调用方需持有 mu 或尚未共享
+func (h *executionHistory) compactLocked() error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, records := range h.tasks {
+		for i := range records {
+			if err := encoder.Encode(&records[i]); err != nil {
+				return fmt.Errorf("failed to encode execution history: %w", err)
+			}
+		}
+	}
+
+	dir := filepath.Dir(h.file)
+	// #nosec G301 -- 目录需要可读权限
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(h.file)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), h.file); err != nil {
+		return fmt.Errorf("failed to replace history file: %w", err)
+	}
+	h.appended = 0
+	return nil
+}
+
+// query 查询执行记录，taskName 为空时查询所有任务，按开始时间倒序返回
+func (h *executionHistory) query(taskName string, filter HistoryFilter) []ExecutionRecord {
+	if h == nil {
+		return nil
+	}
+
+	h.mu.RLock()
+	var result []ExecutionRecord
+	for name, records := range h.tasks {
+		if taskName != "" && name != taskName {
+			continue
+		}
+		for i := range records {
+			if filter.match(&records[i]) {
+				result = append(result, records[i])
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartTime.After(result[j].StartTime)
+	})
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result
+}
+
+// newExecutionRecord 根据执行结果创建执行记录，status 为空时根据返回给调度中心的结果判断
+func newExecutionRecord(run taskRun, start time.Time, status ExecutionStatus, result string) ExecutionRecord {
+	end := time.Now()
+	record := ExecutionRecord{
+		TaskName:   run.taskName,
+		LogID:      run.logID,
+		JobID:      run.jobID,
+		ShardIndex: run.shardIndex,
+		ShardTotal: run.shardTotal,
+		StartTime:  start,
+		EndTime:    end,
+		Duration:   end.Sub(start),
+		Status:     status,
+		Message:    result,
+	}
+
+	prefix, detail, _ := strings.Cut(result, ": ")
+	if record.Status == "" {
+		switch prefix {
+		case "SUCCESS":
+			record.Status = ExecutionSuccess
+		case "CIRCUIT_OPEN":
+			record.Status = ExecutionCircuitOpen
+		default:
+			record.Status = ExecutionFailed
+		}
+	}
+	if record.Status != ExecutionSuccess {
+		record.Error = detail
+	}
+	return record
+}
+
+// ExecutionHistory 查询本执行器的执行历史，taskName 为空时查询所有任务，按开始时间倒序返回
+func (e *executorImpl) ExecutionHistory(taskName string, filter HistoryFilter) []ExecutionRecord {
+	return e.history.query(taskName, filter)
+}
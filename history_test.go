@@ -0,0 +1,198 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// historyBase 测试记录的起始时间
+var historyBase = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// testRecord 创建第 i 条测试记录，开始时间按 i 递增
+func testRecord(task string, i int, status ExecutionStatus) ExecutionRecord {
+	return ExecutionRecord{
+		TaskName:  task,
+		LogID:     int64(i),
+		JobID:     int64(i % 2),
+		StartTime: historyBase.Add(time.Duration(i) * time.Minute),
+		Status:    status,
+	}
+}
+
+// fileLines 返回文件的行数
+func fileLines(t *testing.T, path string) int {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	n := 0
+	for scanner := bufio.NewScanner(file); scanner.Scan(); {
+		n++
+	}
+	return n
+}
+
+// logIDs 返回记录的日志 ID
+func logIDs(records []ExecutionRecord) []int64 {
+	ids := make([]int64, len(records))
+	for i, r := range records {
+		ids[i] = r.LogID
+	}
+	return ids
+}
+
+func TestExecutionHistoryQuery(t *testing.T) {
+	h, err := newExecutionHistory(HistoryConfig{Size: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 5; i++ {
+		status := ExecutionSuccess
+		if i%2 == 0 {
+			status = ExecutionFailed
+		}
+		h.add(testRecord("a", i, status))
+	}
+	h.add(testRecord("b", 6, ExecutionRejected))
+
+	tests := []struct {
+		name   string
+		task   string
+		filter HistoryFilter
+		want   []int64
+	}{
+		{"bounded per task, newest first", "a", HistoryFilter{}, []int64{5, 4, 3}},
+		{"all tasks", "", HistoryFilter{}, []int64{6, 5, 4, 3}},
+		{"status", "a", HistoryFilter{Status: ExecutionFailed}, []int64{4}},
+		{"log id", "", HistoryFilter{LogID: 6}, []int64{6}},
+		{"job id", "a", HistoryFilter{JobID: 1}, []int64{5, 3}},
+		{"since inclusive", "a", HistoryFilter{Since: historyBase.Add(4 * time.Minute)}, []int64{5, 4}},
+		{"until exclusive", "a", HistoryFilter{Until: historyBase.Add(4 * time.Minute)}, []int64{3}},
+		{"limit", "", HistoryFilter{Limit: 2}, []int64{6, 5}},
+		{"unknown task", "c", HistoryFilter{}, []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := logIDs(h.query(tt.task, tt.filter))
+			if len(got) != len(tt.want) {
+				t.Fatalf("query = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("query = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	disabled, err := newExecutionHistory(HistoryConfig{Disabled: true})
+	if err != nil || disabled != nil {
+		t.Fatalf("disabled history = %v, %v; want nil", disabled, err)
+	}
+	disabled.add(testRecord("a", 1, ExecutionSuccess))
+	if got := disabled.query("a", HistoryFilter{}); got != nil {
+		t.Errorf("disabled query = %v, want nil", got)
+	}
+}
+
+func TestExecutionHistoryPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "history.jsonl")
+	cfg := HistoryConfig{Size: 2, File: path}
+
+	h, err := newExecutionHistory(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.add(testRecord("a", 1, ExecutionSuccess))
+	h.add(testRecord("a", 2, ExecutionFailed))
+	if n := fileLines(t, path); n != 2 {
+		t.Fatalf("file lines = %d, want 2", n)
+	}
+
+	// 追加的记录超过内存中保留的记录数时压缩文件
+	h.add(testRecord("a", 3, ExecutionSuccess))
+	if n := fileLines(t, path); n != 2 {
+		t.Errorf("file lines after compaction = %d, want 2", n)
+	}
+
+	// 写入中途崩溃留下的半行和无效记录在加载时忽略
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString("{\"task_name\":\"\"}\n{\"task_name\":\"a\",\"log_id\":"); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := newExecutionHistory(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reloaded.query("a", HistoryFilter{})
+	if ids := logIDs(got); len(ids) != 2 || ids[0] != 3 || ids[1] != 2 {
+		t.Fatalf("reloaded records = %v, want [3 2]", ids)
+	}
+	if got[1].Status != ExecutionFailed || !got[0].StartTime.Equal(historyBase.Add(3*time.Minute)) {
+		t.Errorf("reloaded records = %+v", got)
+	}
+	// 加载后压缩，文件中只保留有效记录
+	if n := fileLines(t, path); n != 2 {
+		t.Errorf("file lines after reload = %d, want 2", n)
+	}
+
+	// 加载时按新的容量裁剪
+	smaller, err := newExecutionHistory(HistoryConfig{Size: 1, File: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := logIDs(smaller.query("", HistoryFilter{})); len(ids) != 1 || ids[0] != 3 {
+		t.Errorf("records with size 1 = %v, want [3]", ids)
+	}
+}
+
+func TestNewExecutionRecordStatus(t *testing.T) {
+	run := taskRun{taskName: "demo", logID: 1}
+	tests := []struct {
+		status    ExecutionStatus
+		result    string
+		want      ExecutionStatus
+		wantError string
+	}{
+		{"", "SUCCESS", ExecutionSuccess, ""},
+		{"", "FAIL: boom", ExecutionFailed, "boom"},
+		{"", "CIRCUIT_OPEN: demo", ExecutionCircuitOpen, "demo"},
+		{ExecutionRejected, "FAIL: concurrency limit reached", ExecutionRejected, "concurrency limit reached"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.result, func(t *testing.T) {
+			record := newExecutionRecord(run, time.Now(), tt.status, tt.result)
+			if record.Status != tt.want || record.Error != tt.wantError || record.Message != tt.result {
+				t.Errorf("record = %+v, want status %s error %q", record, tt.want, tt.wantError)
+			}
+		})
+	}
+}
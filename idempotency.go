@@ -0,0 +1,263 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-anyway/framework-metrics"
+)
+
+// defaultIdempotencyTTL 默认幂等记录保留时间
+const defaultIdempotencyTTL = 24 * time.Hour
+
+// ErrDuplicateInProgress 相同幂等键的执行仍在进行中
+var ErrDuplicateInProgress = errors.New("duplicate execution is in progress")
+
+// IdempotencyStatus 幂等记录状态
+type IdempotencyStatus string
+
+const (
+	IdempotencyRunning   IdempotencyStatus = "running"   // 执行中
+	IdempotencySucceeded IdempotencyStatus = "succeeded" // 执行成功
+	IdempotencyFailed    IdempotencyStatus = "failed"    // 执行失败
+)
+
+// IdempotencyRecord 幂等记录
+type IdempotencyRecord struct {
+	Key         string            `json:"key"`
+	Status      IdempotencyStatus `json:"status"`
+	Error       string            `json:"error,omitempty"` // 失败时的错误信息
+	LogID       int64             `json:"log_id"`          // 首次执行的日志 ID
+	StartedAt   time.Time         `json:"started_at"`
+	CompletedAt time.Time         `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time         `json:"expires_at"`
+}
+
+// IdempotencyStore 幂等记录存储接口
+type IdempotencyStore interface {
+	// Reserve 原子地占用幂等键并写入 running 记录
+	// 键已存在且未过期时不做修改，返回已有记录；占用成功时返回 nil
+	Reserve(ctx context.Context, record IdempotencyRecord) (*IdempotencyRecord, error)
+
+	// Complete 写入执行结果
+	Complete(ctx context.Context, record IdempotencyRecord) error
+
+	// Delete 删除幂等记录，使后续执行可以重新进行
+	Delete(ctx context.Context, key string) error
+}
+
+// IdempotencyKeyFunc 生成幂等键，返回空字符串表示本次执行不做幂等检查
+type IdempotencyKeyFunc func(ctx context.Context, param string) (string, error)
+
+// LogIDKey 使用任务名称和日志 ID 作为幂等键，拦截调度中心重复下发的同一次调度
+func LogIDKey(ctx context.Context, _ string) (string, error) {
+	info, ok := RunInfoFromContext(ctx)
+	if !ok || info.LogID <= 0 {
+		return "", nil
+	}
+	return "log:" + info.TaskName + ":" + strconv.FormatInt(info.LogID, 10), nil
+}
+
+// ParamFieldKey 使用 JSON 参数中的业务字段作为幂等键（支持 a.b 形式的嵌套字段）
+// 任务名称和各字段值编码为 JSON 数组，不同的字段值（包括含分隔符的字符串、字符串 "1" 与数字 1）不会生成相同的键。
+// 字段缺失时返回错误，避免无意中跳过幂等检查
+func ParamFieldKey(fields ...string) IdempotencyKeyFunc {
+	return func(ctx context.Context, param string) (string, error) {
+		var payload map[string]interface{}
+		decoder := json.NewDecoder(strings.NewReader(param))
+		decoder.UseNumber()
+		if err := decoder.Decode(&payload); err != nil {
+			return "", fmt.Errorf("param must be a JSON object for idempotency key: %w", err)
+		}
+
+		info, _ := RunInfoFromContext(ctx)
+		parts := []interface{}{info.TaskName}
+		for _, field := range fields {
+			value, ok := lookupJSONField(payload, field)
+			if !ok {
+				return "", fmt.Errorf("idempotency key field %q not found in param", field)
+			}
+			parts = append(parts, value)
+		}
+		encoded, err := json.Marshal(parts)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode idempotency key: %w", err)
+		}
+		return "biz:" + string(encoded), nil
+	}
+}
+
+// lookupJSONField 按 a.b 形式的路径查找 JSON 字段
+func lookupJSONField(payload map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = payload
+	for _, segment := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = obj[segment]; !ok || current == nil {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// idempotencyOptions 幂等中间件选项
+type idempotencyOptions struct {
+	ttl          time.Duration
+	recordErrors bool
+}
+
+// IdempotencyOption 幂等中间件选项函数
+type IdempotencyOption func(*idempotencyOptions)
+
+// WithIdempotencyTTL 设置幂等记录保留时间（默认 24h）
+func WithIdempotencyTTL(ttl time.Duration) IdempotencyOption {
+	return func(o *idempotencyOptions) {
+		if ttl > 0 {
+			o.ttl = ttl
+		}
+	}
+}
+
+// WithIdempotencyRecordErrors 记录失败结果，重复执行时直接返回之前的错误
+// 默认不记录失败结果，失败后删除记录以便重试
+func WithIdempotencyRecordErrors() IdempotencyOption {
+	return func(o *idempotencyOptions) {
+		o.recordErrors = true
+	}
+}
+
+// IdempotencyMiddleware 幂等中间件
+// 相同幂等键的重复执行直接返回之前记录的结果，不再调用处理器；
+// 重复执行会写入任务日志并计入 xxljob_task_duplicates_total 指标。
+// 处理器 panic 时删除记录（与默认的失败处理一致）；进程在执行中异常退出时，running 记录会保留到过期为止。
+// keyFn 为空时使用 LogIDKey
+func IdempotencyMiddleware(store IdempotencyStore, keyFn IdempotencyKeyFunc, opts ...IdempotencyOption) Middleware {
+	o := idempotencyOptions{ttl: defaultIdempotencyTTL}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if keyFn == nil {
+		keyFn = LogIDKey
+	}
+
+	return func(next TaskHandler) TaskHandler {
+		return func(ctx context.Context, param string) error {
+			key, err := keyFn(ctx, param)
+			if err != nil {
+				return fmt.Errorf("failed to build idempotency key: %w", err)
+			}
+			if key == "" {
+				return next(ctx, param)
+			}
+
+			info, _ := RunInfoFromContext(ctx)
+			now := time.Now()
+			existing, err := store.Reserve(ctx, IdempotencyRecord{
+				Key:       key,
+				Status:    IdempotencyRunning,
+				LogID:     info.LogID,
+				StartedAt: now,
+				ExpiresAt: now.Add(o.ttl),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to reserve idempotency key %s: %w", key, err)
+			}
+			if existing != nil {
+				return duplicateResult(ctx, info.TaskName, existing)
+			}
+
+			// 记录结果使用独立的 context，避免任务超时导致结果丢失
+			storeCtx := context.WithoutCancel(ctx)
+
+			// 处理器 panic 时删除 running 记录，以便之后重新触发，panic 继续向外传递
+			returned := false
+			defer func() {
+				if !returned {
+					if deleteErr := store.Delete(storeCtx, key); deleteErr != nil {
+						warnIdempotency(ctx, "Failed to delete idempotency record", key, deleteErr)
+					}
+				}
+			}()
+
+			err = next(ctx, param)
+			returned = true
+			if err != nil && !o.recordErrors {
+				if deleteErr := store.Delete(storeCtx, key); deleteErr != nil {
+					warnIdempotency(ctx, "Failed to delete idempotency record", key, deleteErr)
+				}
+				return err
+			}
+
+			record := IdempotencyRecord{
+				Key:         key,
+				Status:      IdempotencySucceeded,
+				LogID:       info.LogID,
+				StartedAt:   now,
+				CompletedAt: time.Now(),
+				ExpiresAt:   time.Now().Add(o.ttl),
+			}
+			if err != nil {
+				record.Status = IdempotencyFailed
+				record.Error = err.Error()
+			}
+			if completeErr := store.Complete(storeCtx, record); completeErr != nil {
+				warnIdempotency(ctx, "Failed to record idempotency result", key, completeErr)
+			}
+			return err
+		}
+	}
+}
+
+// duplicateResult 记录重复执行并返回之前的结果
+func duplicateResult(ctx context.Context, taskName string, existing *IdempotencyRecord) error {
+	if metrics.IsEnabled() {
+		xxlJobDuplicateTotal.WithLabelValues(taskName, string(existing.Status)).Inc()
+	}
+	if logWriter := JobLogFromContext(ctx); logWriter != nil {
+		logWriter.Warn("Duplicate execution skipped",
+			"idempotency_key", existing.Key,
+			"status", existing.Status,
+			"first_log_id", existing.LogID,
+			"started_at", existing.StartedAt.Format(time.RFC3339),
+		)
+	}
+
+	switch existing.Status {
+	case IdempotencySucceeded:
+		return nil
+	case IdempotencyFailed:
+		return fmt.Errorf("duplicate of failed execution (log_id=%d): %s", existing.LogID, existing.Error)
+	default:
+		return fmt.Errorf("%w: log_id=%d", ErrDuplicateInProgress, existing.LogID)
+	}
+}
+
+// warnIdempotency 将幂等存储错误写入任务日志
+func warnIdempotency(ctx context.Context, msg, key string, err error) {
+	if logWriter := JobLogFromContext(ctx); logWriter != nil {
+		logWriter.Warn(msg, "idempotency_key", key, "error", err)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// MemoryIdempotencyStore 内存幂等记录存储
+// 仅能拦截同一进程内的重复执行，过期记录在写入时清理
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]IdempotencyRecord
+}
+
+// NewMemoryIdempotencyStore 创建内存幂等记录存储
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		records: make(map[string]IdempotencyRecord),
+	}
+}
+
+// Reserve 占用幂等键
+func (s *MemoryIdempotencyStore) Reserve(_ context.Context, record IdempotencyRecord) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, r := range s.records {
+		if now.After(r.ExpiresAt) {
+			delete(s.records, key)
+		}
+	}
+
+	if existing, ok := s.records[record.Key]; ok {
+		return &existing, nil
+	}
+	s.records[record.Key] = record
+	return nil, nil
+}
+
+// Complete 写入执行结果
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, record IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[record.Key] = record
+	return nil
+}
+
+// Delete 删除幂等记录
+func (s *MemoryIdempotencyStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// FileIdempotencyStore 文件幂等记录存储
+// 每个幂等键对应目录下一个 JSON 文件，可在同一主机或共享文件系统上的多个执行器之间共享
+type FileIdempotencyStore struct {
+	dir string
+	mu  sync.Mutex // 串行化同一进程内的过期记录替换
+}
+
+// NewFileIdempotencyStore 创建文件幂等记录存储（会自动创建目录）
+func NewFileIdempotencyStore(dir string) (*FileIdempotencyStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("idempotency directory cannot be empty")
+	}
+	// #nosec G301 -- 目录需要多个进程可访问
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create idempotency directory: %w", err)
+	}
+	return &FileIdempotencyStore{dir: dir}, nil
+}
+
+// path 返回幂等键对应的文件路径（键做哈希，避免非法文件名）
+func (s *FileIdempotencyStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Reserve 占用幂等键
+// 先写临时文件再硬链接到目标路径，目标已存在时链接失败，保证占用的原子性且不会读到半写的记录。
+// 多个进程同时替换同一条过期记录时存在很小的竞争窗口
+func (s *FileIdempotencyStore) Reserve(_ context.Context, record IdempotencyRecord) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmpPath, err := s.writeTemp(record)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmpPath)
+	path := s.path(record.Key)
+
+	// 最多尝试两次：第一次发现过期记录时删除后重试
+	for attempt := 0; attempt < 2; attempt++ {
+		err := os.Link(tmpPath, path)
+		if err == nil {
+			return nil, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create idempotency record: %w", err)
+		}
+
+		existing, err := s.read(path)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil && time.Now().Before(existing.ExpiresAt) {
+			return existing, nil
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove expired idempotency record: %w", err)
+		}
+	}
+	return nil, fmt.Errorf("failed to reserve idempotency key %s: concurrent update", record.Key)
+}
+
+// Complete 写入执行结果（先写临时文件再重命名，避免读到不完整的记录）
+func (s *FileIdempotencyStore) Complete(_ context.Context, record IdempotencyRecord) error {
+	tmpPath, err := s.writeTemp(record)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, s.path(record.Key)); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to save idempotency record: %w", err)
+	}
+	return nil
+}
+
+// writeTemp 将记录写入目录下的临时文件，返回临时文件路径
+func (s *FileIdempotencyStore) writeTemp(record IdempotencyRecord) (string, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode idempotency record: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write idempotency record: %w", writeErr)
+	}
+	return tmp.Name(), nil
+}
+
+// Delete 删除幂等记录
+func (s *FileIdempotencyStore) Delete(_ context.Context, key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove idempotency record: %w", err)
+	}
+	return nil
+}
+
+// read 读取幂等记录，文件不存在时返回 nil
+func (s *FileIdempotencyStore) read(path string) (*IdempotencyRecord, error) {
+	// #nosec G304 -- 文件路径由配置目录和键哈希组成
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read idempotency record: %w", err)
+	}
+
+	var record IdempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		// 记录损坏（例如写入过程中进程退出）按过期处理
+		return nil, nil
+	}
+	return &record, nil
+}
@@ -0,0 +1,234 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParamFieldKey(t *testing.T) {
+	ctx := contextWithRunInfo(context.Background(), taskRun{taskName: "demo"})
+
+	tests := []struct {
+		name   string
+		fields []string
+		param  string
+		want   string
+	}{
+		{"single field", []string{"order_id"}, `{"order_id":"A1"}`, `biz:["demo","A1"]`},
+		{"nested field", []string{"order.id"}, `{"order":{"id":42}}`, `biz:["demo",42]`},
+		{"large number keeps precision", []string{"id"}, `{"id":12345678901234567890}`, `biz:["demo",12345678901234567890]`},
+		{"separator in value", []string{"a", "b"}, `{"a":"x:y","b":"z"}`, `biz:["demo","x:y","z"]`},
+		{"separator moved", []string{"a", "b"}, `{"a":"x","b":"y:z"}`, `biz:["demo","x","y:z"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParamFieldKey(tt.fields...)(ctx, tt.param)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("key = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// 字面上拼接相同、实际不同的字段值必须生成不同的键
+	distinct := []string{
+		`{"a":"x:y","b":"z"}`,
+		`{"a":"x","b":"y:z"}`,
+		`{"a":"1","b":"z"}`,
+		`{"a":1,"b":"z"}`,
+	}
+	seen := make(map[string]string)
+	for _, param := range distinct {
+		key, err := ParamFieldKey("a", "b")(ctx, param)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if other, ok := seen[key]; ok {
+			t.Errorf("params %s and %s share key %s", other, param, key)
+		}
+		seen[key] = param
+	}
+
+	for _, param := range []string{`{"a":"x"}`, `{"a":"x","b":null}`, `not json`, `[1,2]`} {
+		if _, err := ParamFieldKey("a", "b")(ctx, param); err == nil {
+			t.Errorf("param %s: error = nil, want error", param)
+		}
+	}
+}
+
+// idempotencyStores 返回待测试的幂等存储实现
+func idempotencyStores(t *testing.T) map[string]IdempotencyStore {
+	t.Helper()
+	fileStore, err := NewFileIdempotencyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]IdempotencyStore{
+		"memory": NewMemoryIdempotencyStore(),
+		"file":   fileStore,
+	}
+}
+
+func TestIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	running := func(key string, logID int64, ttl time.Duration) IdempotencyRecord {
+		return IdempotencyRecord{
+			Key:       key,
+			Status:    IdempotencyRunning,
+			LogID:     logID,
+			StartedAt: now,
+			ExpiresAt: now.Add(ttl),
+		}
+	}
+
+	for name, store := range idempotencyStores(t) {
+		t.Run(name, func(t *testing.T) {
+			existing, err := store.Reserve(ctx, running("k", 1, time.Hour))
+			if err != nil || existing != nil {
+				t.Fatalf("first Reserve = %+v, %v; want nil, nil", existing, err)
+			}
+
+			existing, err = store.Reserve(ctx, running("k", 2, time.Hour))
+			if err != nil || existing == nil || existing.LogID != 1 || existing.Status != IdempotencyRunning {
+				t.Fatalf("duplicate Reserve = %+v, %v; want running record of log 1", existing, err)
+			}
+
+			completed := running("k", 1, time.Hour)
+			completed.Status = IdempotencySucceeded
+			completed.CompletedAt = now
+			if err := store.Complete(ctx, completed); err != nil {
+				t.Fatal(err)
+			}
+			existing, err = store.Reserve(ctx, running("k", 3, time.Hour))
+			if err != nil || existing == nil || existing.Status != IdempotencySucceeded {
+				t.Fatalf("Reserve after Complete = %+v, %v; want succeeded record", existing, err)
+			}
+
+			if err := store.Delete(ctx, "k"); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Delete(ctx, "k"); err != nil {
+				t.Fatalf("deleting missing key error = %v", err)
+			}
+			existing, err = store.Reserve(ctx, running("k", 4, time.Hour))
+			if err != nil || existing != nil {
+				t.Fatalf("Reserve after Delete = %+v, %v; want nil, nil", existing, err)
+			}
+
+			// 过期记录会被替换
+			if _, err := store.Reserve(ctx, running("expired", 5, -time.Second)); err != nil {
+				t.Fatal(err)
+			}
+			existing, err = store.Reserve(ctx, running("expired", 6, time.Hour))
+			if err != nil || existing != nil {
+				t.Fatalf("Reserve over expired record = %+v, %v; want nil, nil", existing, err)
+			}
+			existing, _ = store.Reserve(ctx, running("expired", 7, time.Hour))
+			if existing == nil || existing.LogID != 6 {
+				t.Fatalf("record after replacement = %+v, want log 6", existing)
+			}
+		})
+	}
+}
+
+func TestIdempotencyStoreConcurrentReserve(t *testing.T) {
+	for name, store := range idempotencyStores(t) {
+		t.Run(name, func(t *testing.T) {
+			const n = 20
+			var (
+				wg      sync.WaitGroup
+				mu      sync.Mutex
+				winners int
+			)
+			for i := 0; i < n; i++ {
+				wg.Add(1)
+				go func(logID int64) {
+					defer wg.Done()
+					existing, err := store.Reserve(context.Background(), IdempotencyRecord{
+						Key:       "shared",
+						Status:    IdempotencyRunning,
+						LogID:     logID,
+						ExpiresAt: time.Now().Add(time.Hour),
+					})
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if existing == nil {
+						mu.Lock()
+						winners++
+						mu.Unlock()
+					}
+				}(int64(i + 1))
+			}
+			wg.Wait()
+			if winners != 1 {
+				t.Errorf("%d Reserve calls succeeded, want 1", winners)
+			}
+		})
+	}
+}
+
+func TestFileIdempotencyStoreFiles(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileIdempotencyStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	record := IdempotencyRecord{Key: "k", Status: IdempotencyRunning, LogID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+
+	if _, err := store.Reserve(ctx, record); err != nil {
+		t.Fatal(err)
+	}
+	// 临时文件在占用后被删除，目录中只保留记录文件
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || filepath.Join(dir, entries[0].Name()) != store.path("k") {
+		t.Fatalf("directory entries = %v, want only the record file", entries)
+	}
+
+	// 损坏的记录按过期处理，可以重新占用
+	if err := os.WriteFile(store.path("k"), []byte("{broken"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	existing, err := store.Reserve(ctx, record)
+	if err != nil || existing != nil {
+		t.Fatalf("Reserve over corrupt record = %+v, %v; want nil, nil", existing, err)
+	}
+
+	// 另一个进程使用同一目录时可以看到已占用的记录
+	other, err := NewFileIdempotencyStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing, err = other.Reserve(ctx, record)
+	if err != nil || existing == nil || existing.LogID != 1 {
+		t.Fatalf("Reserve from another store = %+v, %v; want existing record", existing, err)
+	}
+}
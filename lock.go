@@ -0,0 +1,216 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultLockTTL 默认租约有效期
+	defaultLockTTL = 30 * time.Second
+	// lockReleaseTimeout 释放租约的超时时间
+	lockReleaseTimeout = 5 * time.Second
+)
+
+var (
+	// ErrLockHeld 锁已被其他执行器持有
+	ErrLockHeld = errors.New("lock is held by another owner")
+	// ErrLockLost 租约已丢失（过期或被删除）
+	ErrLockLost = errors.New("lock lease lost")
+)
+
+// Locker 分布式锁后端接口
+type Locker interface {
+	// Acquire 尝试获取指定名称的租约（不等待），已被持有时返回 ErrLockHeld
+	Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error)
+}
+
+// Lease 已获取的租约
+type Lease interface {
+	// Renew 续期租约，租约已丢失时返回 ErrLockLost
+	Renew(ctx context.Context, ttl time.Duration) error
+
+	// Release 释放租约
+	Release(ctx context.Context) error
+}
+
+// LockKeyFunc 根据任务参数生成锁名称
+type LockKeyFunc func(ctx context.Context, param string) string
+
+// lockOptions 锁中间件选项
+type lockOptions struct {
+	ttl        time.Duration
+	failOnHeld bool
+}
+
+// LockOption 锁中间件选项函数
+type LockOption func(*lockOptions)
+
+// WithLockTTL 设置租约有效期（默认 30s），任务运行期间每 1/3 有效期续期一次
+func WithLockTTL(ttl time.Duration) LockOption {
+	return func(o *lockOptions) {
+		if ttl > 0 {
+			o.ttl = ttl
+		}
+	}
+}
+
+// WithLockFailOnHeld 锁被持有时返回 ErrLockHeld（默认跳过本次执行并返回成功）
+func WithLockFailOnHeld() LockOption {
+	return func(o *lockOptions) {
+		o.failOnHeld = true
+	}
+}
+
+// TaskLockKey 使用任务名称作为锁名称
+// context 中没有调度信息时返回空字符串，LockMiddleware 会拒绝执行
+func TaskLockKey(ctx context.Context, _ string) string {
+	info, ok := RunInfoFromContext(ctx)
+	if !ok || info.TaskName == "" {
+		return ""
+	}
+	return "xxljob:" + info.TaskName
+}
+
+// LockMiddleware 分布式锁中间件
+// 执行前获取租约，运行期间定期续期；租约丢失时取消任务 context。
+// 用于防止广播路由或调度中心故障切换时同一任务在多个执行器上并发执行。
+// keyFn 为空时使用 TaskLockKey；keyFn 返回空字符串时不获取锁，直接返回错误
+func LockMiddleware(locker Locker, keyFn LockKeyFunc, opts ...LockOption) Middleware {
+	o := lockOptions{ttl: defaultLockTTL}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if keyFn == nil {
+		keyFn = TaskLockKey
+	}
+
+	return func(next TaskHandler) TaskHandler {
+		return func(ctx context.Context, param string) error {
+			key := keyFn(ctx, param)
+			if key == "" {
+				return fmt.Errorf("lock key is empty (task name not found in context)")
+			}
+			logWriter := JobLogFromContext(ctx)
+
+			lease, err := locker.Acquire(ctx, key, o.ttl)
+			if errors.Is(err, ErrLockHeld) {
+				if logWriter != nil {
+					logWriter.Warn("Task skipped: lock is held by another executor", "lock", key)
+				}
+				if o.failOnHeld {
+					return fmt.Errorf("%w: %s", ErrLockHeld, key)
+				}
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to acquire lock %s: %w", key, err)
+			}
+
+			lockCtx, cancel := context.WithCancelCause(ctx)
+			renewDone := make(chan struct{})
+			go func() {
+				defer close(renewDone)
+				keepLease(lockCtx, lease, o.ttl, func(renewErr error) {
+					if logWriter != nil {
+						logWriter.Error("Lock lease lost, cancelling task", "lock", key, "error", renewErr)
+					}
+					cancel(ErrLockLost)
+				})
+			}()
+
+			// stop 停止续期并释放锁，返回锁是否已丢失（已丢失时不释放，避免释放其他执行器的锁）
+			// 先等待续期 goroutine 退出再读取取消原因，保证之后不会再有租约丢失的通知
+			stop := func() bool {
+				cancel(nil)
+				<-renewDone
+				lost := errors.Is(context.Cause(lockCtx), ErrLockLost)
+
+				if !lost {
+					releaseCtx, releaseCancel := context.WithTimeout(context.WithoutCancel(ctx), lockReleaseTimeout)
+					if releaseErr := lease.Release(releaseCtx); releaseErr != nil && logWriter != nil {
+						logWriter.Warn("Failed to release lock", "lock", key, "error", releaseErr)
+					}
+					releaseCancel()
+				}
+				return lost
+			}
+			// 处理器 panic 时同样停止续期并释放锁（任务 context 不会在任务结束时取消），panic 继续向外传递
+			returned := false
+			defer func() {
+				if !returned {
+					stop()
+				}
+			}()
+
+			err = next(lockCtx, param)
+			returned = true
+			if lost := stop(); lost {
+				// 租约丢失后其他执行器可能已经开始执行，无论处理器是否返回错误都按失败上报
+				if err != nil {
+					return fmt.Errorf("%w: %s: %w", ErrLockLost, key, err)
+				}
+				return fmt.Errorf("%w: %s", ErrLockLost, key)
+			}
+			return err
+		}
+	}
+}
+
+// keepLease 定期续期租约，直到 ctx 结束
+// 续期返回 ErrLockLost，或连续失败超过一个有效期时调用 onLost
+func keepLease(ctx context.Context, lease Lease, ttl time.Duration, onLost func(error)) {
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	lastRenewed := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := lease.Renew(ctx, ttl)
+		if err == nil {
+			lastRenewed = time.Now()
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, ErrLockLost) || time.Since(lastRenewed) >= ttl {
+			onLost(err)
+			return
+		}
+	}
+}
+
+// newLockToken 生成租约持有者标识
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
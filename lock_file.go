@@ -0,0 +1,117 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileLocker 基于文件锁（flock）的锁
+// 适用于同一主机或共享文件系统上的多个执行器。锁随文件描述符持有，
+// 进程退出时由操作系统自动释放，因此 ttl 仅用于续期检查
+type FileLocker struct {
+	dir string
+}
+
+// NewFileLocker 创建文件锁（会自动创建目录）
+func NewFileLocker(dir string) (*FileLocker, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("lock directory cannot be empty")
+	}
+	// #nosec G301 -- 锁目录需要多个进程可访问
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	return &FileLocker{dir: dir}, nil
+}
+
+// Acquire 尝试获取文件锁
+func (l *FileLocker) Acquire(_ context.Context, key string, _ time.Duration) (Lease, error) {
+	path := filepath.Join(l.dir, url.PathEscape(key)+".lock")
+
+	// #nosec G302,G304 -- 锁文件路径来自配置的目录
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := tryLockFile(file); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	// 记录持有者信息，便于排查
+	hostname, _ := os.Hostname()
+	if err := file.Truncate(0); err == nil {
+		_, _ = fmt.Fprintf(file, "pid=%d host=%s acquired_at=%s\n", os.Getpid(), hostname, time.Now().Format(time.RFC3339))
+	}
+	return &fileLease{path: path, file: file}, nil
+}
+
+// fileLease 文件锁租约
+type fileLease struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// Renew 检查锁文件仍然存在且未被替换
+func (l *fileLease) Renew(_ context.Context, _ time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return ErrLockLost
+	}
+	held, err := l.file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat lock file: %w", err)
+	}
+	current, err := os.Stat(l.path)
+	if os.IsNotExist(err) {
+		return ErrLockLost
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat lock file: %w", err)
+	}
+	if !os.SameFile(held, current) {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// Release 释放文件锁（保留锁文件，避免删除与加锁之间的竞争）
+func (l *fileLease) Release(_ context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	unlockErr := unlockFile(l.file)
+	closeErr := l.file.Close()
+	l.file = nil
+	if unlockErr != nil {
+		return fmt.Errorf("failed to unlock file: %w", unlockErr)
+	}
+	return closeErr
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+//go:build !unix
+
+package xxljob
+
+import (
+	"fmt"
+	"os"
+)
+
+// tryLockFile 非 Unix 平台不支持文件锁
+func tryLockFile(file *os.File) error {
+	return fmt.Errorf("file lock is not supported on this platform")
+}
+
+// unlockFile 非 Unix 平台不支持文件锁
+func unlockFile(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Redis 锁脚本：只有持有者（token 匹配）才能续期和释放
+const (
+	redisAcquireScript = `if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then return 1 else return 0 end`
+	redisRenewScript   = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("PEXPIRE", KEYS[1], ARGV[2]) else return 0 end`
+	redisReleaseScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+)
+
+// RedisClient 执行 Lua 脚本的 Redis 客户端
+// 返回值为脚本的整数结果（int64、int 或数字字符串均可）
+type RedisClient interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// RedisEvalFunc 函数形式的 RedisClient，便于适配现有客户端，例如 go-redis：
+//
+//	xxljob.RedisEvalFunc(func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+//		return rdb.Eval(ctx, script, keys, args...).Result()
+//	})
+type RedisEvalFunc func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+
+// Eval 执行脚本
+func (f RedisEvalFunc) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return f(ctx, script, keys, args...)
+}
+
+// RedisLocker 基于 Redis SET NX PX 的分布式锁
+type RedisLocker struct {
+	client RedisClient
+	prefix string
+}
+
+// NewRedisLocker 创建 Redis 分布式锁，prefix 会添加到所有锁名称之前
+func NewRedisLocker(client RedisClient, prefix string) (*RedisLocker, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client cannot be nil")
+	}
+	return &RedisLocker{client: client, prefix: prefix}, nil
+}
+
+// Acquire 尝试获取租约
+func (l *RedisLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error) {
+	token, err := newLockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	lease := &redisLease{client: l.client, key: l.prefix + key, token: token}
+	ok, err := lease.eval(ctx, redisAcquireScript, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire redis lock: %w", err)
+	}
+	if !ok {
+		return nil, ErrLockHeld
+	}
+	return lease, nil
+}
+
+// redisLease Redis 租约
+type redisLease struct {
+	client RedisClient
+	key    string
+	token  string
+}
+
+// Renew 续期租约
+func (l *redisLease) Renew(ctx context.Context, ttl time.Duration) error {
+	ok, err := l.eval(ctx, redisRenewScript, ttl)
+	if err != nil {
+		return fmt.Errorf("failed to renew redis lock: %w", err)
+	}
+	if !ok {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// Release 释放租约（租约已丢失时不报错）
+func (l *redisLease) Release(ctx context.Context) error {
+	if _, err := l.eval(ctx, redisReleaseScript, 0); err != nil {
+		return fmt.Errorf("failed to release redis lock: %w", err)
+	}
+	return nil
+}
+
+// eval 执行锁脚本，返回结果是否为 1
+func (l *redisLease) eval(ctx context.Context, script string, ttl time.Duration) (bool, error) {
+	args := []interface{}{l.token}
+	if ttl > 0 {
+		args = append(args, strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	result, err := l.client.Eval(ctx, script, []string{l.key}, args...)
+	if err != nil {
+		return false, err
+	}
+
+	switch v := result.(type) {
+	case int64:
+		return v == 1, nil
+	case int:
+		return v == 1, nil
+	case string:
+		return v == "1", nil
+	case []byte:
+		return string(v) == "1", nil
+	case nil:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected redis reply: %T", result)
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis 在内存中模拟锁脚本的 Redis
+type fakeRedis struct {
+	mu     sync.Mutex
+	values map[string]string
+	expiry map[string]time.Time
+	evals  int
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{
+		values: make(map[string]string),
+		expiry: make(map[string]time.Time),
+	}
+}
+
+// Eval 按脚本内容模拟 SET NX PX、token 校验后的 PEXPIRE 和 DEL
+func (r *fakeRedis) Eval(_ context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.evals++
+
+	key, token := keys[0], args[0].(string)
+	if at, ok := r.expiry[key]; ok && !time.Now().Before(at) {
+		delete(r.values, key)
+		delete(r.expiry, key)
+	}
+	current, exists := r.values[key]
+
+	switch script {
+	case redisAcquireScript:
+		if exists {
+			return int64(0), nil
+		}
+		r.values[key] = token
+		r.expiry[key] = time.Now().Add(r.ttl(args[1]))
+		return int64(1), nil
+	case redisRenewScript:
+		if !exists || current != token {
+			return int64(0), nil
+		}
+		r.expiry[key] = time.Now().Add(r.ttl(args[1]))
+		return int64(1), nil
+	case redisReleaseScript:
+		if !exists || current != token {
+			return int64(0), nil
+		}
+		delete(r.values, key)
+		delete(r.expiry, key)
+		return int64(1), nil
+	default:
+		return nil, fmt.Errorf("unexpected script: %s", script)
+	}
+}
+
+func (r *fakeRedis) ttl(arg interface{}) time.Duration {
+	ms, _ := strconv.ParseInt(arg.(string), 10, 64)
+	return time.Duration(ms) * time.Millisecond
+}
+
+// steal 模拟租约过期后被其他执行器获取
+func (r *fakeRedis) steal(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[key] = "other"
+	delete(r.expiry, key)
+}
+
+func (r *fakeRedis) holder(key string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v, ok := r.values[key]
+	return v, ok
+}
+
+// lockTestContext 返回带有调度信息的 context
+func lockTestContext(taskName string) context.Context {
+	return contextWithRunInfo(context.Background(), taskRun{taskName: taskName})
+}
+
+func TestRedisLocker(t *testing.T) {
+	redis := newFakeRedis()
+	locker, err := NewRedisLocker(redis, "app:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	lease, err := locker.Acquire(ctx, "job", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := locker.Acquire(ctx, "job", time.Minute); !errors.Is(err, ErrLockHeld) {
+		t.Fatalf("second Acquire error = %v, want ErrLockHeld", err)
+	}
+	if err := lease.Renew(ctx, time.Minute); err != nil {
+		t.Fatalf("Renew error = %v", err)
+	}
+
+	redis.steal("app:job")
+	if err := lease.Renew(ctx, time.Minute); !errors.Is(err, ErrLockLost) {
+		t.Fatalf("Renew after steal error = %v, want ErrLockLost", err)
+	}
+	// 租约已丢失时释放不能删除其他持有者的锁
+	if err := lease.Release(ctx); err != nil {
+		t.Fatalf("Release error = %v", err)
+	}
+	if holder, _ := redis.holder("app:job"); holder != "other" {
+		t.Fatalf("holder after stale Release = %q, want other", holder)
+	}
+}
+
+func TestRedisLockerReplies(t *testing.T) {
+	tests := []struct {
+		reply   interface{}
+		ok      bool
+		wantErr bool
+	}{
+		{int64(1), true, false},
+		{1, true, false},
+		{"1", true, false},
+		{[]byte("1"), true, false},
+		{int64(0), false, false},
+		{nil, false, false},
+		{1.0, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%T(%v)", tt.reply, tt.reply), func(t *testing.T) {
+			client := RedisEvalFunc(func(context.Context, string, []string, ...interface{}) (interface{}, error) {
+				return tt.reply, nil
+			})
+			locker, _ := NewRedisLocker(client, "")
+			_, err := locker.Acquire(context.Background(), "job", time.Minute)
+			switch {
+			case tt.wantErr:
+				if err == nil || errors.Is(err, ErrLockHeld) {
+					t.Errorf("Acquire error = %v, want reply error", err)
+				}
+			case tt.ok:
+				if err != nil {
+					t.Errorf("Acquire error = %v", err)
+				}
+			default:
+				if !errors.Is(err, ErrLockHeld) {
+					t.Errorf("Acquire error = %v, want ErrLockHeld", err)
+				}
+			}
+		})
+	}
+}
+
+func TestLockMiddleware(t *testing.T) {
+	handlerErr := errors.New("handler failed")
+
+	tests := []struct {
+		name    string
+		opts    []LockOption
+		held    bool                             // 执行前锁已被其他执行器持有
+		handler func(*fakeRedis) TaskHandler     // 处理器
+		check   func(t *testing.T, err error)    // 检查返回值
+		holder  func(t *testing.T, r *fakeRedis) // 检查执行后的锁状态
+	}{
+		{
+			name: "released after success",
+			handler: func(*fakeRedis) TaskHandler {
+				return func(context.Context, string) error { return nil }
+			},
+			check: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("error = %v", err)
+				}
+			},
+			holder: func(t *testing.T, r *fakeRedis) {
+				if _, ok := r.holder("xxljob:demo"); ok {
+					t.Error("lock not released")
+				}
+			},
+		},
+		{
+			name: "held skips",
+			held: true,
+			handler: func(*fakeRedis) TaskHandler {
+				return func(context.Context, string) error { t.Error("handler called"); return nil }
+			},
+			check: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("error = %v, want nil", err)
+				}
+			},
+		},
+		{
+			name: "held fails",
+			opts: []LockOption{WithLockFailOnHeld()},
+			held: true,
+			handler: func(*fakeRedis) TaskHandler {
+				return func(context.Context, string) error { t.Error("handler called"); return nil }
+			},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, ErrLockHeld) {
+					t.Errorf("error = %v, want ErrLockHeld", err)
+				}
+			},
+		},
+		{
+			name: "lost lease reported even when handler succeeds",
+			opts: []LockOption{WithLockTTL(30 * time.Millisecond)},
+			handler: func(r *fakeRedis) TaskHandler {
+				return func(ctx context.Context, _ string) error {
+					r.steal("xxljob:demo")
+					select {
+					case <-ctx.Done():
+					case <-time.After(5 * time.Second):
+						t.Error("task context not cancelled after lease lost")
+					}
+					return nil
+				}
+			},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, ErrLockLost) {
+					t.Errorf("error = %v, want ErrLockLost", err)
+				}
+			},
+			holder: func(t *testing.T, r *fakeRedis) {
+				if holder, _ := r.holder("xxljob:demo"); holder != "other" {
+					t.Errorf("holder = %q, want other", holder)
+				}
+			},
+		},
+		{
+			name: "lost lease keeps handler error",
+			opts: []LockOption{WithLockTTL(30 * time.Millisecond)},
+			handler: func(r *fakeRedis) TaskHandler {
+				return func(ctx context.Context, _ string) error {
+					r.steal("xxljob:demo")
+					<-ctx.Done()
+					return handlerErr
+				}
+			},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, ErrLockLost) || !errors.Is(err, handlerErr) {
+					t.Errorf("error = %v, want ErrLockLost wrapping handler error", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redis := newFakeRedis()
+			locker, _ := NewRedisLocker(redis, "")
+			if tt.held {
+				redis.steal("xxljob:demo")
+			}
+
+			handler := LockMiddleware(locker, nil, tt.opts...)(tt.handler(redis))
+			tt.check(t, handler(lockTestContext("demo"), ""))
+			if tt.holder != nil {
+				tt.holder(t, redis)
+			}
+		})
+	}
+}
+
+func TestLockMiddlewareReleasesOnPanic(t *testing.T) {
+	redis := newFakeRedis()
+	locker, _ := NewRedisLocker(redis, "")
+	handler := LockMiddleware(locker, nil)(func(context.Context, string) error {
+		panic("boom")
+	})
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recovered %v, want boom", r)
+			}
+		}()
+		_ = handler(lockTestContext("demo"), "")
+	}()
+
+	if _, ok := redis.holder("xxljob:demo"); ok {
+		t.Error("lock not released after panic")
+	}
+}
+
+func TestLockMiddlewareRequiresKey(t *testing.T) {
+	redis := newFakeRedis()
+	locker, _ := NewRedisLocker(redis, "")
+	handler := LockMiddleware(locker, nil)(func(context.Context, string) error {
+		t.Error("handler called without lock key")
+		return nil
+	})
+
+	if err := handler(context.Background(), ""); err == nil {
+		t.Error("error = nil, want missing lock key error")
+	}
+	if redis.evals != 0 {
+		t.Errorf("redis called %d times, want 0", redis.evals)
+	}
+}
@@ -19,11 +19,12 @@ package xxljob
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-anyway/framework-log"
@@ -35,10 +36,63 @@ import (
 const (
 	// defaultLogPageSize 默认日志分页大小（行数）
 	defaultLogPageSize = 1000
-	// maxLogFileSize 最大日志文件大小（10MB），超过此大小强制分页
-	maxLogFileSize = 10 * 1024 * 1024
+	// defaultLogFlushInterval 默认日志刷新间隔
+	defaultLogFlushInterval = time.Second
+	// defaultLogBufferSize 默认日志缓冲区大小（32KB）
+	defaultLogBufferSize = 32 * 1024
 )
 
+// LogSyncPolicy 日志落盘策略
+// 控制 LogWriter 何时调用 fsync 将日志持久化到磁盘
+type LogSyncPolicy int
+
+const (
+	// SyncEveryLine 每写入一行立即刷新并 fsync（最安全，但最慢）
+	SyncEveryLine LogSyncPolicy = iota
+	// SyncInterval 按刷新间隔或缓冲区满时刷新并 fsync
+	SyncInterval
+	// SyncOnClose 按刷新间隔刷新到操作系统，仅在关闭时 fsync
+	SyncOnClose
+)
+
+// String 返回策略名称
+func (p LogSyncPolicy) String() string {
+	switch p {
+	case SyncEveryLine:
+		return "every_line"
+	case SyncInterval:
+		return "interval"
+	case SyncOnClose:
+		return "on_close"
+	default:
+		return fmt.Sprintf("LogSyncPolicy(%d)", int(p))
+	}
+}
+
+// ParseLogSyncPolicy 解析日志落盘策略名称
+// 支持 every_line、interval、on_close，空字符串返回 SyncInterval
+func ParseLogSyncPolicy(name string) (LogSyncPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "interval":
+		return SyncInterval, nil
+	case "every_line":
+		return SyncEveryLine, nil
+	case "on_close":
+		return SyncOnClose, nil
+	default:
+		return SyncInterval, fmt.Errorf("unknown log sync policy: %s", name)
+	}
+}
+
+// logWriterOptions 日志写入器配置
+type logWriterOptions struct {
+	syncPolicy    LogSyncPolicy
+	flushInterval time.Duration
+	bufferSize    int
+	format        LogFormat // 日志行格式
+	level         LogLevel  // 最低输出级别
+}
+
 // contextKey 用于在 context 中存储 LogWriter 的 key
 type contextKey string
 
@@ -56,110 +110,374 @@ func LogWriterFromContext(ctx context.Context) LogWriter {
 	return nil
 }
 
+// JobLog 当前任务的日志
+// 在 LogWriter 的基础上提供分级日志、刷新以及 io.Writer 能力；
+// Write 实现 io.Writer，格式化写入使用 Printf。nil 的 *JobLog 可以安全调用，写入会被丢弃
+type JobLog struct {
+	w *logWriter
+}
+
+// JobLogFromContext 从 context 中获取当前任务的日志
+// 不在任务上下文中（或未配置 LogStore）时返回 nil
+func JobLogFromContext(ctx context.Context) *JobLog {
+	if ctx == nil {
+		return nil
+	}
+	if w, ok := ctx.Value(logWriterKey).(*logWriter); ok && w != nil {
+		return &JobLog{w: w}
+	}
+	return nil
+}
+
+// Write 实现 io.Writer，以 INFO 级别按行写入任务日志
+// 不完整的行会缓存到下一次写入换行符或任务结束时输出
+func (l *JobLog) Write(p []byte) (int, error) {
+	if l == nil {
+		return len(p), nil
+	}
+	return l.w.out.Write(p)
+}
+
+// Printf 写入一行日志（自动添加时间戳，INFO 级别），等同于 LogWriter.Write
+func (l *JobLog) Printf(format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+	l.w.Write(format, args...)
+}
+
+// WriteLine 写入一行日志（不添加时间戳），等同于 LogWriter.WriteLine
+func (l *JobLog) WriteLine(line string) {
+	if l == nil {
+		return
+	}
+	l.w.WriteLine(line)
+}
+
+// Debug 写入 DEBUG 级别日志，keysAndValues 为交替出现的键值对
+func (l *JobLog) Debug(msg string, keysAndValues ...interface{}) {
+	if l == nil {
+		return
+	}
+	l.w.log(LogLevelDebug, msg, keysAndValues)
+}
+
+// Info 写入 INFO 级别日志，keysAndValues 为交替出现的键值对
+func (l *JobLog) Info(msg string, keysAndValues ...interface{}) {
+	if l == nil {
+		return
+	}
+	l.w.log(LogLevelInfo, msg, keysAndValues)
+}
+
+// Warn 写入 WARN 级别日志，keysAndValues 为交替出现的键值对
+func (l *JobLog) Warn(msg string, keysAndValues ...interface{}) {
+	if l == nil {
+		return
+	}
+	l.w.log(LogLevelWarn, msg, keysAndValues)
+}
+
+// Error 写入 ERROR 级别日志，keysAndValues 为交替出现的键值对
+func (l *JobLog) Error(msg string, keysAndValues ...interface{}) {
+	if l == nil {
+		return
+	}
+	l.w.log(LogLevelError, msg, keysAndValues)
+}
+
+// Flush 将缓冲中的日志立即写入存储
+// 是否同时 fsync 由落盘策略决定（SyncOnClose 不会 fsync）
+func (l *JobLog) Flush() error {
+	if l == nil {
+		return nil
+	}
+	return l.w.Flush()
+}
+
+// Writer 返回写入当前任务日志的 io.WriteCloser
+// 按换行符切分为多行，每行以指定级别和前缀写入；不完整的行会缓存到下次写入或 Close 时输出
+func (l *JobLog) Writer(level LogLevel, prefix string) io.WriteCloser {
+	if l == nil {
+		return newLineWriter(prefix, func(string) {})
+	}
+	return newLineWriter(prefix, func(line string) {
+		l.w.log(level, line, nil)
+	})
+}
+
 // logWriter 日志写入器实现
+// 日志先写入内存缓冲区，按刷新间隔或缓冲区大小刷新到 LogStore，
+// 是否 fsync 由 LogSyncPolicy 决定
 type logWriter struct {
-	logPath string
-	logID   int64
-	file    *os.File
-	mu      sync.Mutex
+	logID int64
+	file  LogSink
+	buf   *bufio.Writer
+	opts  logWriterOptions
+	mu    sync.Mutex
+	done  chan struct{}
+	out   *lineWriter // JobLog 作为 io.Writer 使用时缓存不完整的行
+
+	events atomic.Pointer[spanLogEvents] // 启用追踪时将日志行记录为 span 事件
 }
 
 // newLogWriter 创建新的日志写入器
-func newLogWriter(logPath string, logID int64) (*logWriter, error) {
-	if logPath == "" || logID == 0 {
-		return nil, fmt.Errorf("log path or log ID is empty")
+func newLogWriter(store LogStore, logID int64, opts logWriterOptions) (*logWriter, error) {
+	if store == nil || logID == 0 {
+		return nil, fmt.Errorf("log store or log ID is empty")
 	}
 
-	// 构建日志文件路径
-	logFileName := fmt.Sprintf("jobhandler-%d.log", logID)
-	logFilePath := filepath.Join(logPath, logFileName)
-
-	// 打开或创建日志文件（追加模式）
-	// #nosec G302,G304 -- 日志文件需要可读权限，文件路径来自配置
-	file, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	// 打开日志写入端（追加模式）
+	file, err := store.OpenWriter(logID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open log file: %w", err)
+		return nil, err
 	}
 
-	return &logWriter{
-		logPath: logPath,
-		logID:   logID,
-		file:    file,
-	}, nil
+	if opts.flushInterval <= 0 {
+		opts.flushInterval = defaultLogFlushInterval
+	}
+	if opts.bufferSize <= 0 {
+		opts.bufferSize = defaultLogBufferSize
+	}
+
+	w := &logWriter{
+		logID: logID,
+		file:  file,
+		buf:   bufio.NewWriterSize(file, opts.bufferSize),
+		opts:  opts,
+		done:  make(chan struct{}),
+	}
+	w.out = newLineWriter("", func(line string) {
+		w.log(LogLevelInfo, line, nil)
+	})
+
+	// 非逐行落盘时，启动后台定时刷新，保证调度中心能及时拉取到日志
+	if opts.syncPolicy != SyncEveryLine {
+		go w.flushLoop()
+	}
+
+	return w, nil
+}
+
+// flushLoop 定时刷新缓冲区
+func (w *logWriter) flushLoop() {
+	ticker := time.NewTicker(w.opts.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			if w.file != nil {
+				// 定时刷新失败不影响任务执行，下次刷新或关闭时会重试
+				_ = w.flushLocked(w.opts.syncPolicy == SyncInterval)
+			}
+			w.mu.Unlock()
+		}
+	}
 }
 
-// Write 写入一行日志（自动添加时间戳）
+// Write 写入一行日志（自动添加时间戳，INFO 级别）
+// 文本格式保持 "[时间] 内容" 的行格式，JSON 格式按 INFO 级别的结构化日志输出
 func (w *logWriter) Write(format string, args ...interface{}) {
-	if w == nil || w.file == nil {
+	if w == nil || LogLevelInfo < w.opts.level {
 		return
 	}
 
+	msg := fmt.Sprintf(format, args...)
+	if w.opts.format == LogFormatJSON {
+		w.log(LogLevelInfo, msg, nil)
+		return
+	}
+	w.writeLine(fmt.Sprintf("[%s] %s\n", time.Now().Format(logTimeLayout), msg))
+	w.recordEvent(LogLevelInfo, msg)
+}
+
+// log 按级别格式化并写入一行日志，低于最低级别的日志会被丢弃
+func (w *logWriter) log(level LogLevel, msg string, keysAndValues []interface{}) {
+	if w == nil || level < w.opts.level {
+		return
+	}
+
+	w.writeLine(formatLogLine(w.opts.format, time.Now(), level, msg, keysAndValues))
+	w.recordEvent(level, msg, keysAndValues...)
+}
+
+// WriteLine 写入一行日志（不添加时间戳，INFO 级别）
+// 文本格式原样写入；JSON 格式作为 msg 输出，保证每一行都是合法的 JSON
+func (w *logWriter) WriteLine(line string) {
+	if w == nil || LogLevelInfo < w.opts.level {
+		return
+	}
+
+	line = strings.TrimSuffix(line, "\n")
+	if w.opts.format == LogFormatJSON {
+		w.log(LogLevelInfo, line, nil)
+		return
+	}
+	w.writeLine(line + "\n")
+	w.recordEvent(LogLevelInfo, line)
+}
+
+// recordEvent 启用追踪时将日志行记录为 span 事件
+func (w *logWriter) recordEvent(level LogLevel, msg string, keysAndValues ...interface{}) {
+	if events := w.events.Load(); events != nil {
+		events.record(level, msg, keysAndValues)
+	}
+}
+
+// writeLine 将一行日志写入缓冲区，并按落盘策略刷新
+func (w *logWriter) writeLine(line string) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	content := fmt.Sprintf(format, args...)
-	logLine := fmt.Sprintf("[%s] %s\n", time.Now().Format("2006-01-02 15:04:05.000"), content)
-	if _, err := w.file.WriteString(logLine); err != nil {
-		// 写入失败时记录警告，但不影响任务执行
+	if w.file == nil {
+		return
+	}
+
+	if _, err := w.buf.WriteString(line); err != nil {
+		// 写入失败时不影响任务执行
 		// 这里不能使用 log 包，因为可能导致循环依赖
 		_ = err
 		return
 	}
 
-	// 立即同步到磁盘，确保调度中心能及时拉取到日志
-	if err := w.file.Sync(); err != nil {
+	if w.opts.syncPolicy == SyncEveryLine {
+		// 立即同步到磁盘，确保调度中心能及时拉取到日志
 		// 同步失败不影响任务执行，但可能导致日志延迟
-		_ = err
+		_ = w.flushLocked(true)
 	}
 }
 
-// WriteLine 写入一行日志（不添加时间戳）
-func (w *logWriter) WriteLine(line string) {
-	if w == nil || w.file == nil {
-		return
+// Flush 将缓冲区中的日志写入文件
+// 是否同时 fsync 由落盘策略决定（SyncOnClose 不会 fsync）
+func (w *logWriter) Flush() error {
+	if w == nil {
+		return nil
 	}
 
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	if !strings.HasSuffix(line, "\n") {
-		line += "\n"
+	if w.file == nil {
+		return nil
 	}
-	if _, err := w.file.WriteString(line); err != nil {
-		_ = err
-		return
+	return w.flushLocked(w.opts.syncPolicy != SyncOnClose)
+}
+
+// flushLocked 刷新缓冲区（调用方需持有锁）
+func (w *logWriter) flushLocked(sync bool) error {
+	if err := w.buf.Flush(); err != nil {
+		return fmt.Errorf("failed to flush log buffer: %w", err)
 	}
+	if sync {
+		if err := w.file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync log file: %w", err)
+		}
+	}
+	return nil
+}
 
-	// 立即同步到磁盘，确保调度中心能及时拉取到日志
-	if err := w.file.Sync(); err != nil {
-		// 同步失败不影响任务执行，但可能导致日志延迟
-		_ = err
+// attachSpanEvents 将内置 LogWriter 后续的日志行记录为 span 事件
+// 返回的函数用于停止记录，并在 span 上写入事件统计
+func attachSpanEvents(writer *JobLog, events *spanLogEvents) (detach func()) {
+	if writer == nil || events == nil {
+		return func() {}
+	}
+	w := writer.w
+	w.events.Store(events)
+	return func() {
+		if events := w.events.Swap(nil); events != nil {
+			events.finish()
+		}
 	}
 }
 
 // Close 关闭日志写入器
 func (w *logWriter) Close() error {
-	if w == nil || w.file == nil {
+	if w == nil {
 		return nil
 	}
 
+	// 先输出 io.Writer 中剩余的不完整行（会获取 w.mu，不能在持有锁时调用）
+	_ = w.out.Close()
+
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	// 关闭前先同步，确保所有日志都已写入磁盘
-	if err := w.file.Sync(); err != nil {
-		// 同步失败不影响关闭
-		_ = err
+	if w.file == nil {
+		return nil
 	}
 
+	close(w.done)
+
+	// 关闭前先刷新并同步，确保所有日志都已写入磁盘
+	flushErr := w.flushLocked(true)
+
 	err := w.file.Close()
 	w.file = nil
-	return err
+	if err != nil {
+		return err
+	}
+	return flushErr
+}
+
+// logWriterSet 正在使用中的日志写入器集合
+// 用于执行器停止时统一刷新尚未落盘的日志
+type logWriterSet struct {
+	mu      sync.Mutex
+	writers map[*logWriter]struct{}
+}
+
+// newLogWriterSet 创建日志写入器集合
+func newLogWriterSet() *logWriterSet {
+	return &logWriterSet{
+		writers: make(map[*logWriter]struct{}),
+	}
+}
+
+// Add 添加日志写入器
+func (s *logWriterSet) Add(w *logWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writers[w] = struct{}{}
+}
+
+// Remove 移除日志写入器
+func (s *logWriterSet) Remove(w *logWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.writers, w)
+}
+
+// FlushAll 刷新并同步所有日志写入器
+func (s *logWriterSet) FlushAll() {
+	s.mu.Lock()
+	writers := make([]*logWriter, 0, len(s.writers))
+	for w := range s.writers {
+		writers = append(writers, w)
+	}
+	s.mu.Unlock()
+
+	for _, w := range writers {
+		w.mu.Lock()
+		if w.file != nil {
+			if err := w.flushLocked(true); err != nil {
+				log.Warn("Failed to flush log writer",
+					zap.Int64("log_id", w.logID),
+					zap.Error(err),
+				)
+			}
+		}
+		w.mu.Unlock()
+	}
 }
 
 // handleLogRequest 处理日志查询请求（管理端查询日志时调用）
-// 优化：支持真正的分页读取，避免大文件内存占用
-func handleLogRequest(req *xxl.LogReq, logPath string) *xxl.LogRes {
+// 通过 LogStore 分页读取，避免大日志内存占用
+func handleLogRequest(req *xxl.LogReq, store LogStore) *xxl.LogRes {
 	if req == nil {
 		log.Error("XXL-JOB log request is nil")
 		return &xxl.LogRes{
@@ -168,42 +486,34 @@ func handleLogRequest(req *xxl.LogReq, logPath string) *xxl.LogRes {
 		}
 	}
 
-	if logPath == "" {
-		log.Error("XXL-JOB log path not configured")
+	if store == nil {
+		log.Error("XXL-JOB log store not configured")
 		return &xxl.LogRes{
 			Code: 500,
-			Msg:  "log path not configured",
+			Msg:  "log store not configured",
 		}
 	}
 
-	// 构建日志文件路径
-	logFileName := fmt.Sprintf("jobhandler-%d.log", req.LogID)
-	logFilePath := filepath.Join(logPath, logFileName)
-
-	// 检查文件是否存在
-	if _, err := os.Stat(logFilePath); os.IsNotExist(err) {
-		log.Warn("XXL-JOB log file not found",
-			zap.String("log_file", logFilePath),
+	// 按行分页读取日志内容
+	page, err := store.ReadPage(req.LogID, req.FromLineNum, defaultLogPageSize)
+	if errors.Is(err, ErrLogNotFound) {
+		log.Warn("XXL-JOB log not found",
 			zap.Int64("log_id", req.LogID),
 		)
 		return &xxl.LogRes{
 			Code: 500,
-			Msg:  fmt.Sprintf("log file not found: %s", logFilePath),
+			Msg:  fmt.Sprintf("log not found: %d", req.LogID),
 		}
 	}
-
-	// 读取日志文件内容（优化：按行分页读取）
-	result, err := readLogFileWithPagination(logFilePath, req.FromLineNum, defaultLogPageSize)
 	if err != nil {
-		log.Warn("XXL-JOB failed to read log file",
-			zap.String("log_file", logFilePath),
+		log.Warn("XXL-JOB failed to read log",
 			zap.Int64("log_id", req.LogID),
 			zap.Int("from_line", req.FromLineNum),
 			zap.Error(err),
 		)
 		return &xxl.LogRes{
 			Code: 500,
-			Msg:  fmt.Sprintf("failed to read log file: %v", err),
+			Msg:  fmt.Sprintf("failed to read log: %v", err),
 		}
 	}
 
@@ -213,196 +523,51 @@ func handleLogRequest(req *xxl.LogReq, logPath string) *xxl.LogRes {
 		Msg:  "success",
 		Content: xxl.LogResContent{
 			FromLineNum: req.FromLineNum,
-			ToLineNum:   result.ToLineNum,
-			LogContent:  result.Content,
-			IsEnd:       result.IsEnd,
+			ToLineNum:   page.ToLineNum,
+			LogContent:  page.Content,
+			IsEnd:       page.IsEnd,
 		},
 	}
 }
 
-// logReadResult 日志读取结果
-type logReadResult struct {
-	Content   string // 日志内容
-	ToLineNum int    // 结束行号
-	IsEnd     bool   // 是否已读取到文件末尾
-}
-
-// readLogFileWithPagination 按行分页读取日志文件内容（优化版本）
-// 使用 bufio.Scanner 逐行读取，避免大文件内存占用
-// 注意：XXL-JOB 的行号约定从 0 开始（第一行是 0，第二行是 1，以此类推）
-// FromLineNum = 0 表示从第 1 行开始读取
-func readLogFileWithPagination(filePath string, fromLineNum int, pageSize int) (*logReadResult, error) {
-	// 检查文件是否存在
-	fileInfo, err := os.Stat(filePath)
-	if os.IsNotExist(err) {
-		return nil, fmt.Errorf("log file not found: %s", filePath)
-	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to stat log file: %w", err)
-	}
-
-	// 检查文件大小，如果文件为空，直接返回
-	if fileInfo.Size() == 0 {
-		// 空文件，返回 fromLineNum（如果 < 0 则返回 0）
-		toLineNum := fromLineNum
-		if toLineNum < 0 {
-			toLineNum = 0
-		}
-		return &logReadResult{
-			Content:   "",
-			ToLineNum: toLineNum,
-			IsEnd:     true,
-		}, nil
-	}
-
-	// 如果文件很大，强制使用分页（防止内存占用过大）
-	forcePagination := fileInfo.Size() > maxLogFileSize
-	if forcePagination && pageSize <= 0 {
-		pageSize = defaultLogPageSize
-	}
-	if pageSize <= 0 {
-		pageSize = defaultLogPageSize
-	}
-
-	// XXL-JOB 行号约定：从 0 开始
-	// FromLineNum = 0 表示从第 1 行开始（文件中的第 1 行对应行号 0）
-	// 如果 fromLineNum < 0，则从第 1 行开始
-	startLineIndex := fromLineNum
-	if startLineIndex < 0 {
-		startLineIndex = 0
-	}
-
-	// 打开文件
-	// #nosec G304 -- 文件路径来自配置，已验证
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open log file: %w", err)
-	}
-	defer file.Close()
-
-	// 使用 bufio.Scanner 逐行读取（内存效率高）
-	scanner := bufio.NewScanner(file)
-	// 设置缓冲区大小（默认 64KB，对于超长行可以增大）
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 1024*1024) // 最大支持 1MB 的单行
-
-	var lines []string
-	lineIndex := -1 // 文件中的行索引（从 0 开始，对应 XXL-JOB 的行号）
-	toLineNum := startLineIndex
-
-	// 逐行读取
-	for scanner.Scan() {
-		lineIndex++ // 行号从 0 开始
-
-		// 跳过 startLineIndex 之前的行
-		if lineIndex < startLineIndex {
-			continue
-		}
-
-		// 读取指定数量的行
-		line := scanner.Text()
-		lines = append(lines, line)
-		toLineNum = lineIndex // XXL-JOB 约定：ToLineNum 是已读取的最后一行（从 0 开始）
-
-		// 如果达到分页大小，停止读取
-		if len(lines) >= pageSize {
-			break
-		}
-	}
-
-	// 检查扫描错误
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to scan log file: %w", err)
-	}
-
-	// 判断是否已读取到文件末尾
-	// 如果读取的行数少于分页大小，说明已经到文件末尾
-	isEnd := len(lines) < pageSize
-
-	// 如果 fromLineNum 超出文件行数，返回空内容
-	if startLineIndex > lineIndex {
-		// 返回的 ToLineNum 应该是文件的最后一行索引（从 0 开始）
-		toLineNum = lineIndex
-		if toLineNum < 0 {
-			toLineNum = 0
-		}
-		return &logReadResult{
-			Content:   "",
-			ToLineNum: toLineNum,
-			IsEnd:     true,
-		}, nil
-	}
-
-	// 拼接日志内容
-	// XXL-JOB 要求：每行末尾必须有换行符（包括最后一行）
-	content := strings.Join(lines, "\n")
-	if len(lines) > 0 {
-		content += "\n"
-	}
-
-	return &logReadResult{
-		Content:   content,
-		ToLineNum: toLineNum,
-		IsEnd:     isEnd,
-	}, nil
-}
-
-// cleanupOldLogs 清理旧日志文件（后台任务）
-// 优化：按文件修改时间清理，支持任务执行过程中的日志追加
-func cleanupOldLogs(logPath string, retentionDays int) {
-	if logPath == "" || retentionDays <= 0 {
+// cleanupOldLogs 清理旧日志（后台任务）
+// 按最后修改时间清理，支持任务执行过程中的日志追加
+func cleanupOldLogs(store LogStore, retentionDays int) {
+	if store == nil || retentionDays <= 0 {
 		return
 	}
 
 	cutoffTime := time.Now().AddDate(0, 0, -retentionDays)
 
-	entries, err := os.ReadDir(logPath)
+	infos, err := store.List()
 	if err != nil {
-		log.Warn("Failed to read log directory",
-			zap.String("log_path", logPath),
-			zap.Error(err),
-		)
+		log.Warn("Failed to list XXL-JOB logs", zap.Error(err))
 		return
 	}
 
 	var deletedCount int
 	var totalSize int64
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		// 只处理 jobhandler-*.log 文件
-		if !strings.HasPrefix(entry.Name(), "jobhandler-") || !strings.HasSuffix(entry.Name(), ".log") {
+	for _, info := range infos {
+		// 删除超过保留天数的日志（基于修改时间）
+		// 注意：使用 ModTime 而不是创建时间，因为任务执行过程中会追加日志
+		if !info.ModTime.Before(cutoffTime) {
 			continue
 		}
-
-		info, err := entry.Info()
-		if err != nil {
+		if err := store.Delete(info.LogID); err != nil {
+			log.Warn("Failed to remove old log",
+				zap.Int64("log_id", info.LogID),
+				zap.Error(err),
+			)
 			continue
 		}
-
-		// 删除超过保留天数的日志文件（基于修改时间）
-		// 注意：使用 ModTime 而不是创建时间，因为任务执行过程中会追加日志
-		if info.ModTime().Before(cutoffTime) {
-			filePath := filepath.Join(logPath, entry.Name())
-			if err := os.Remove(filePath); err != nil {
-				log.Warn("Failed to remove old log file",
-					zap.String("log_file", filePath),
-					zap.Error(err),
-				)
-			} else {
-				deletedCount++
-				totalSize += info.Size()
-			}
-		}
+		deletedCount++
+		totalSize += info.Size
 	}
 
 	// 汇总清理结果
 	if deletedCount > 0 {
-		log.Info("Cleaned up old XXL-JOB log files",
-			zap.String("log_path", logPath),
+		log.Info("Cleaned up old XXL-JOB logs",
 			zap.Int("deleted_count", deletedCount),
 			zap.Int64("freed_size_mb", totalSize/(1024*1024)),
 			zap.Int("retention_days", retentionDays),
@@ -0,0 +1,142 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-anyway/framework-log"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const jobLoggerKey = contextKey("xxljob_job_logger")
+
+// jobLoggerContext 任务日志上下文
+// 保存任务字段和日志写入器，在获取 Logger 时再构建，确保能拿到最新的 traceID
+type jobLoggerContext struct {
+	fields []zap.Field
+	writer *JobLog
+}
+
+// contextWithJobLogger 将任务日志上下文注入到 context
+func contextWithJobLogger(ctx context.Context, taskName string, logID int64, writer *JobLog) context.Context {
+	return context.WithValue(ctx, jobLoggerKey, &jobLoggerContext{
+		fields: []zap.Field{
+			zap.String("task_name", taskName),
+			zap.Int64("log_id", logID),
+		},
+		writer: writer,
+	})
+}
+
+// LoggerFromContext 获取任务执行期间使用的 Logger
+// 返回的 Logger 基于 framework-log 的 log.FromContext，自动附加 task_name、log_id 字段，
+// 并且同时写入当前任务的 LogWriter，使日志在中心化日志和管理端日志中都可见。
+// 不在任务上下文中时，等同于 log.FromContext(ctx)。
+func LoggerFromContext(ctx context.Context) *zap.Logger {
+	logger := log.FromContext(ctx)
+	if ctx == nil {
+		return logger
+	}
+
+	jl, ok := ctx.Value(jobLoggerKey).(*jobLoggerContext)
+	if !ok || jl == nil {
+		return logger
+	}
+
+	if jl.writer != nil {
+		writer := jl.writer
+		logger = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(core, newJobLogCore(writer))
+		}))
+	}
+	return logger.With(jl.fields...)
+}
+
+// jobLogCore 将 zap 日志写入任务 LogWriter 的 zapcore.Core 实现
+type jobLogCore struct {
+	writer *JobLog
+	fields []zapcore.Field
+}
+
+// newJobLogCore 创建任务日志 Core
+func newJobLogCore(writer *JobLog) zapcore.Core {
+	return &jobLogCore{writer: writer}
+}
+
+// Enabled 任务日志的级别过滤由 LogWriter 负责，这里全部放行
+func (c *jobLogCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With 附加字段
+func (c *jobLogCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &jobLogCore{writer: c.writer, fields: merged}
+}
+
+// Check 检查是否需要记录
+func (c *jobLogCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+// Write 将日志条目转换为键值对并写入 LogWriter
+func (c *jobLogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	// 按键名排序，保证输出稳定
+	keys := make([]string, 0, len(enc.Fields))
+	for k := range enc.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	keysAndValues := make([]interface{}, 0, len(keys)*2)
+	for _, k := range keys {
+		keysAndValues = append(keysAndValues, k, enc.Fields[k])
+	}
+
+	switch {
+	case entry.Level >= zapcore.ErrorLevel:
+		c.writer.Error(entry.Message, keysAndValues...)
+	case entry.Level == zapcore.WarnLevel:
+		c.writer.Warn(entry.Message, keysAndValues...)
+	case entry.Level == zapcore.InfoLevel:
+		c.writer.Info(entry.Message, keysAndValues...)
+	default:
+		c.writer.Debug(entry.Message, keysAndValues...)
+	}
+	return nil
+}
+
+// Sync 刷新 LogWriter 缓冲区
+func (c *jobLogCore) Sync() error {
+	return c.writer.Flush()
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logTimeLayout 任务日志时间格式
+const logTimeLayout = "2006-01-02 15:04:05.000"
+
+// LogLevel 任务日志级别
+type LogLevel int
+
+const (
+	// LogLevelDebug 调试级别
+	LogLevelDebug LogLevel = iota
+	// LogLevelInfo 信息级别（Write 默认使用此级别）
+	LogLevelInfo
+	// LogLevelWarn 警告级别
+	LogLevelWarn
+	// LogLevelError 错误级别
+	LogLevelError
+)
+
+// String 返回级别名称（大写，与管理端日志中显示的一致）
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// ParseLogLevel 解析日志级别名称（不区分大小写）
+// 空字符串返回 LogLevelInfo
+func ParseLogLevel(name string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LogLevelDebug, nil
+	case "", "info":
+		return LogLevelInfo, nil
+	case "warn", "warning":
+		return LogLevelWarn, nil
+	case "error":
+		return LogLevelError, nil
+	default:
+		return LogLevelInfo, fmt.Errorf("unknown log level: %s", name)
+	}
+}
+
+// LogFormat 任务日志行格式
+type LogFormat int
+
+const (
+	// LogFormatText 纯文本格式，兼容管理端日志查看器
+	// 示例：[2006-01-02 15:04:05.000] [INFO] message key=value
+	LogFormatText LogFormat = iota
+	// LogFormatJSON JSON Lines 格式，每行一个 JSON 对象
+	LogFormatJSON
+)
+
+// String 返回格式名称
+func (f LogFormat) String() string {
+	switch f {
+	case LogFormatText:
+		return "text"
+	case LogFormatJSON:
+		return "json"
+	default:
+		return fmt.Sprintf("LogFormat(%d)", int(f))
+	}
+}
+
+// ParseLogFormat 解析日志格式名称
+// 空字符串返回 LogFormatText
+func ParseLogFormat(name string) (LogFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "text":
+		return LogFormatText, nil
+	case "json":
+		return LogFormatJSON, nil
+	default:
+		return LogFormatText, fmt.Errorf("unknown log format: %s", name)
+	}
+}
+
+// formatLogLine 按指定格式生成一行日志（包含结尾换行符）
+// keysAndValues 为交替出现的键值对，键不是字符串或缺少值时会被修正
+func formatLogLine(format LogFormat, t time.Time, level LogLevel, msg string, keysAndValues []interface{}) string {
+	fields := normalizeLogFields(keysAndValues)
+
+	if format == LogFormatJSON {
+		var b strings.Builder
+		b.WriteString(`{"time":`)
+		writeJSONValue(&b, t.Format(logTimeLayout))
+		b.WriteString(`,"level":`)
+		writeJSONValue(&b, level.String())
+		b.WriteString(`,"msg":`)
+		writeJSONValue(&b, msg)
+		for _, f := range fields {
+			b.WriteByte(',')
+			writeJSONValue(&b, f.key)
+			b.WriteByte(':')
+			writeJSONValue(&b, f.value)
+		}
+		b.WriteString("}\n")
+		return b.String()
+	}
+
+	var b strings.Builder
+	b.WriteString("[")
+	b.WriteString(t.Format(logTimeLayout))
+	b.WriteString("] [")
+	b.WriteString(level.String())
+	b.WriteString("] ")
+	b.WriteString(msg)
+	for _, f := range fields {
+		b.WriteByte(' ')
+		b.WriteString(f.key)
+		b.WriteByte('=')
+		b.WriteString(formatTextValue(f.value))
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// logField 日志字段
+type logField struct {
+	key   string
+	value interface{}
+}
+
+// normalizeLogFields 将键值对列表转换为字段列表
+func normalizeLogFields(keysAndValues []interface{}) []logField {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+
+	fields := make([]logField, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		if i+1 >= len(keysAndValues) {
+			// 缺少值时记录为 !MISSING，避免静默丢失
+			fields = append(fields, logField{key: key, value: "!MISSING"})
+			break
+		}
+		fields = append(fields, logField{key: key, value: keysAndValues[i+1]})
+	}
+	return fields
+}
+
+// writeJSONValue 写入 JSON 值，无法序列化时退化为字符串
+func writeJSONValue(b *strings.Builder, v interface{}) {
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v))
+	}
+	b.Write(data)
+}
+
+// formatTextValue 格式化文本格式中的字段值，包含空白或引号时加引号
+func formatTextValue(v interface{}) string {
+	var s string
+	switch val := v.(type) {
+	case string:
+		s = val
+	case error:
+		s = val.Error()
+	case fmt.Stringer:
+		s = val.String()
+	default:
+		s = fmt.Sprint(val)
+	}
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,246 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrLogNotFound 日志不存在
+var ErrLogNotFound = errors.New("log not found")
+
+// LogStore 任务日志存储后端接口
+// 执行器通过此接口写入任务日志，并在管理端查询日志时分页读取
+type LogStore interface {
+	// OpenWriter 打开指定日志 ID 的写入端（追加模式）
+	OpenWriter(logID int64) (LogSink, error)
+
+	// ReadPage 从 fromLineNum 开始读取最多 pageSize 行（行号从 0 开始）
+	// 日志不存在时返回 ErrLogNotFound
+	ReadPage(logID int64, fromLineNum int, pageSize int) (*LogPage, error)
+
+	// Delete 删除指定日志 ID 的日志
+	Delete(logID int64) error
+
+	// List 列出所有日志的基本信息（用于过期清理）
+	List() ([]LogInfo, error)
+}
+
+// LogSink 日志写入端
+// Sync 应保证已写入的内容对 ReadPage 可见并尽量持久化
+type LogSink interface {
+	io.Writer
+	Sync() error
+	Close() error
+}
+
+// LogPage 日志分页读取结果
+type LogPage struct {
+	Content   string // 日志内容（每行以换行符结尾）
+	ToLineNum int    // 结束行号（已读取的最后一行，从 0 开始）
+	IsEnd     bool   // 是否已读取到日志末尾
+}
+
+// LogInfo 日志基本信息
+type LogInfo struct {
+	LogID   int64     // 日志 ID
+	Size    int64     // 日志大小（字节）
+	ModTime time.Time // 最后修改时间
+}
+
+// logFileName 返回日志 ID 对应的文件名（与 XXL-JOB 官方执行器保持一致）
+func logFileName(logID int64) string {
+	return fmt.Sprintf("jobhandler-%d.log", logID)
+}
+
+// parseLogFileName 从文件名中解析日志 ID
+func parseLogFileName(name string) (int64, bool) {
+	if !strings.HasPrefix(name, "jobhandler-") || !strings.HasSuffix(name, ".log") {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, "jobhandler-"), ".log"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// readLogPage 按行分页读取日志内容
+// 使用 bufio.Scanner 逐行读取，避免大日志内存占用
+// 注意：XXL-JOB 的行号约定从 0 开始（第一行是 0，第二行是 1，以此类推）
+// FromLineNum = 0 表示从第 1 行开始读取
+func readLogPage(r io.Reader, fromLineNum int, pageSize int) (*LogPage, error) {
+	if pageSize <= 0 {
+		pageSize = defaultLogPageSize
+	}
+
+	// 如果 fromLineNum < 0，则从第 1 行开始
+	startLineIndex := fromLineNum
+	if startLineIndex < 0 {
+		startLineIndex = 0
+	}
+
+	// 使用 bufio.Scanner 逐行读取（内存效率高）
+	scanner := bufio.NewScanner(r)
+	// 设置缓冲区大小（默认 64KB，对于超长行可以增大）
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 1024*1024) // 最大支持 1MB 的单行
+
+	var lines []string
+	lineIndex := -1 // 日志中的行索引（从 0 开始，对应 XXL-JOB 的行号）
+	toLineNum := startLineIndex
+
+	for scanner.Scan() {
+		lineIndex++
+
+		// 跳过 startLineIndex 之前的行
+		if lineIndex < startLineIndex {
+			continue
+		}
+
+		lines = append(lines, scanner.Text())
+		toLineNum = lineIndex // XXL-JOB 约定：ToLineNum 是已读取的最后一行（从 0 开始）
+
+		// 如果达到分页大小，停止读取
+		if len(lines) >= pageSize {
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan log: %w", err)
+	}
+
+	// 空日志，返回 startLineIndex
+	if lineIndex < 0 {
+		return &LogPage{ToLineNum: startLineIndex, IsEnd: true}, nil
+	}
+
+	// 如果 fromLineNum 超出日志行数，返回空内容，ToLineNum 为最后一行索引
+	if startLineIndex > lineIndex {
+		return &LogPage{ToLineNum: lineIndex, IsEnd: true}, nil
+	}
+
+	// 拼接日志内容
+	// XXL-JOB 要求：每行末尾必须有换行符（包括最后一行）
+	content := strings.Join(lines, "\n")
+	if len(lines) > 0 {
+		content += "\n"
+	}
+
+	return &LogPage{
+		Content:   content,
+		ToLineNum: toLineNum,
+		// 如果读取的行数少于分页大小，说明已经到日志末尾
+		IsEnd: len(lines) < pageSize,
+	}, nil
+}
+
+// FileLogStore 本地文件日志存储
+// 每个日志 ID 对应目录下的 jobhandler-<id>.log 文件
+type FileLogStore struct {
+	dir string
+}
+
+// NewFileLogStore 创建本地文件日志存储（会自动创建目录）
+func NewFileLogStore(dir string) (*FileLogStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("log directory cannot be empty")
+	}
+	// #nosec G301 -- 日志目录需要可读权限
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	return &FileLogStore{dir: dir}, nil
+}
+
+// path 返回日志文件路径
+func (s *FileLogStore) path(logID int64) string {
+	return filepath.Join(s.dir, logFileName(logID))
+}
+
+// OpenWriter 打开或创建日志文件（追加模式）
+func (s *FileLogStore) OpenWriter(logID int64) (LogSink, error) {
+	// #nosec G302,G304 -- 日志文件需要可读权限，文件路径来自配置
+	file, err := os.OpenFile(s.path(logID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	return file, nil
+}
+
+// ReadPage 分页读取日志文件
+func (s *FileLogStore) ReadPage(logID int64, fromLineNum int, pageSize int) (*LogPage, error) {
+	filePath := s.path(logID)
+
+	// #nosec G304 -- 文件路径来自配置，已验证
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrLogNotFound, filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer file.Close()
+
+	return readLogPage(file, fromLineNum, pageSize)
+}
+
+// Delete 删除日志文件
+func (s *FileLogStore) Delete(logID int64) error {
+	if err := os.Remove(s.path(logID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove log file: %w", err)
+	}
+	return nil
+}
+
+// List 列出目录下所有 jobhandler-*.log 文件
+func (s *FileLogStore) List() ([]LogInfo, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log directory: %w", err)
+	}
+
+	infos := make([]LogInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		logID, ok := parseLogFileName(entry.Name())
+		if !ok {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		infos = append(infos, LogInfo{
+			LogID:   logID,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+	return infos, nil
+}
@@ -0,0 +1,205 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultMemoryLogCapacity 内存日志存储默认保留的日志数量
+	defaultMemoryLogCapacity = 1000
+	// defaultMemoryLogMaxLines 内存日志存储中单个日志默认保留的最大行数
+	defaultMemoryLogMaxLines = 10000
+)
+
+// MemoryLogStore 内存环形缓冲日志存储
+// 未配置 LogPath 时使用，最多保留 capacity 个日志，超出时淘汰最早创建的日志；
+// 单个日志超过 maxLines 行时丢弃最早的行，行号保持不变
+type MemoryLogStore struct {
+	capacity int
+	maxLines int
+
+	mu    sync.Mutex
+	logs  map[int64]*list.Element
+	order *list.List // 按创建顺序排列，元素为 *memoryLog
+}
+
+// memoryLog 单个内存日志
+type memoryLog struct {
+	logID     int64
+	lines     []string // 已完成的行
+	firstLine int      // lines[0] 对应的行号（被丢弃的行数）
+	partial   bytes.Buffer
+	size      int64
+	modTime   time.Time
+}
+
+// NewMemoryLogStore 创建内存日志存储
+// capacity、maxLines 小于等于 0 时使用默认值
+func NewMemoryLogStore(capacity, maxLines int) *MemoryLogStore {
+	if capacity <= 0 {
+		capacity = defaultMemoryLogCapacity
+	}
+	if maxLines <= 0 {
+		maxLines = defaultMemoryLogMaxLines
+	}
+	return &MemoryLogStore{
+		capacity: capacity,
+		maxLines: maxLines,
+		logs:     make(map[int64]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// OpenWriter 打开内存日志写入端，日志不存在时创建
+func (s *MemoryLogStore) OpenWriter(logID int64) (LogSink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.logs[logID]; !ok {
+		s.logs[logID] = s.order.PushBack(&memoryLog{logID: logID, modTime: time.Now()})
+		for s.order.Len() > s.capacity {
+			oldest := s.order.Front()
+			s.order.Remove(oldest)
+			delete(s.logs, oldest.Value.(*memoryLog).logID)
+		}
+	}
+	return &memorySink{store: s, logID: logID}, nil
+}
+
+// append 追加日志内容
+func (s *MemoryLogStore) append(logID int64, p []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.logs[logID]
+	if !ok {
+		// 日志已被淘汰，写入被丢弃
+		return nil
+	}
+	ml := elem.Value.(*memoryLog)
+	ml.partial.Write(p)
+	ml.size += int64(len(p))
+	ml.modTime = time.Now()
+
+	for {
+		idx := bytes.IndexByte(ml.partial.Bytes(), '\n')
+		if idx < 0 {
+			break
+		}
+		ml.lines = append(ml.lines, string(ml.partial.Next(idx + 1)[:idx]))
+	}
+
+	if over := len(ml.lines) - s.maxLines; over > 0 {
+		ml.lines = append([]string(nil), ml.lines[over:]...)
+		ml.firstLine += over
+	}
+	return nil
+}
+
+// ReadPage 分页读取内存日志
+func (s *MemoryLogStore) ReadPage(logID int64, fromLineNum int, pageSize int) (*LogPage, error) {
+	s.mu.Lock()
+	elem, ok := s.logs[logID]
+	if !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%w: %d", ErrLogNotFound, logID)
+	}
+	ml := elem.Value.(*memoryLog)
+	firstLine := ml.firstLine
+	lines := make([]string, len(ml.lines), len(ml.lines)+1)
+	copy(lines, ml.lines)
+	if ml.partial.Len() > 0 {
+		lines = append(lines, ml.partial.String())
+	}
+	s.mu.Unlock()
+
+	// 已被丢弃的行无法读取，从最早保留的行开始
+	if fromLineNum < firstLine {
+		fromLineNum = firstLine
+	}
+
+	var content string
+	if len(lines) > 0 {
+		content = strings.Join(lines, "\n") + "\n"
+	}
+	page, err := readLogPage(strings.NewReader(content), fromLineNum-firstLine, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	page.ToLineNum += firstLine
+	return page, nil
+}
+
+// Delete 删除内存日志
+func (s *MemoryLogStore) Delete(logID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.logs[logID]; ok {
+		s.order.Remove(elem)
+		delete(s.logs, logID)
+	}
+	return nil
+}
+
+// List 列出所有内存日志
+func (s *MemoryLogStore) List() ([]LogInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]LogInfo, 0, s.order.Len())
+	for elem := s.order.Front(); elem != nil; elem = elem.Next() {
+		ml := elem.Value.(*memoryLog)
+		infos = append(infos, LogInfo{
+			LogID:   ml.logID,
+			Size:    ml.size,
+			ModTime: ml.modTime,
+		})
+	}
+	return infos, nil
+}
+
+// memorySink 内存日志写入端
+type memorySink struct {
+	store *MemoryLogStore
+	logID int64
+}
+
+// Write 追加日志内容
+func (w *memorySink) Write(p []byte) (int, error) {
+	if err := w.store.append(w.logID, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Sync 内存写入立即可见，无需同步
+func (w *memorySink) Sync() error {
+	return nil
+}
+
+// Close 关闭写入端
+func (w *memorySink) Close() error {
+	return nil
+}
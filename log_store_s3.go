@@ -0,0 +1,697 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultS3Timeout S3 请求默认超时时间
+	defaultS3Timeout = 10 * time.Second
+	// defaultS3UploadInterval 默认日志分块上传间隔
+	defaultS3UploadInterval = 5 * time.Second
+	// defaultS3ChunkSize 默认日志分块大小（1MB）
+	defaultS3ChunkSize = 1024 * 1024
+	// s3ChunkNameFormat 分块对象名称，为分块第一行的行号（补零保证字典序与行号顺序一致）
+	s3ChunkNameFormat = "%012d"
+)
+
+// S3LogStoreConfig S3 兼容对象存储配置
+// 可用于 AWS S3、MinIO 等兼容 S3 API 的对象存储
+type S3LogStoreConfig struct {
+	Endpoint        string        `yaml:"endpoint" env:"XXL_JOB_LOG_S3_ENDPOINT"` // 例如 https://s3.amazonaws.com 或 http://127.0.0.1:9000
+	Region          string        `yaml:"region" env:"XXL_JOB_LOG_S3_REGION" default:"us-east-1"`
+	Bucket          string        `yaml:"bucket" env:"XXL_JOB_LOG_S3_BUCKET"`
+	Prefix          string        `yaml:"prefix" env:"XXL_JOB_LOG_S3_PREFIX"` // 对象 key 前缀，例如 xxl-job/my-executor/
+	AccessKeyID     string        `yaml:"access_key_id" env:"XXL_JOB_LOG_S3_ACCESS_KEY_ID"`
+	SecretAccessKey string        `yaml:"secret_access_key" env:"XXL_JOB_LOG_S3_SECRET_ACCESS_KEY"`
+	SessionToken    string        `yaml:"session_token" env:"XXL_JOB_LOG_S3_SESSION_TOKEN"`
+	UsePathStyle    bool          `yaml:"use_path_style" env:"XXL_JOB_LOG_S3_USE_PATH_STYLE" default:"false"` // MinIO 等本地服务通常需要开启
+	Timeout         time.Duration `yaml:"timeout" env:"XXL_JOB_LOG_S3_TIMEOUT" default:"10s"`
+	UploadInterval  time.Duration `yaml:"upload_interval" env:"XXL_JOB_LOG_S3_UPLOAD_INTERVAL" default:"5s"` // 后台上传新日志分块的间隔
+	ChunkSize       int           `yaml:"chunk_size" env:"XXL_JOB_LOG_S3_CHUNK_SIZE" default:"1048576"`      // 缓存达到该大小（字节）时立即上传
+}
+
+// Validate 验证配置
+func (c *S3LogStoreConfig) Validate() error {
+	if c.Endpoint == "" {
+		return fmt.Errorf("s3 endpoint is required")
+	}
+	if c.Bucket == "" {
+		return fmt.Errorf("s3 bucket is required")
+	}
+	if _, err := url.Parse(c.Endpoint); err != nil {
+		return fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+	return nil
+}
+
+// S3LogStore S3 兼容对象存储日志存储
+// 由于对象存储不支持追加写入，每个日志 ID 对应 <prefix>jobhandler-<id>.log/ 下的一组只追加的分块对象，
+// 分块对象以其第一行的行号命名，分页读取时只下载包含目标行的分块。
+// 写入端在内存中缓存新内容，由后台按 UploadInterval 或缓存达到 ChunkSize 时上传为新分块；
+// Sync 只触发一次后台上传，不等待上传完成（因此 SyncEveryLine 不会每行发起一次请求），Close 时上传剩余内容
+type S3LogStore struct {
+	cfg      S3LogStoreConfig
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// NewS3LogStore 创建 S3 兼容对象存储日志存储
+func NewS3LogStore(cfg S3LogStoreConfig) (*S3LogStore, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultS3Timeout
+	}
+	if cfg.UploadInterval <= 0 {
+		cfg.UploadInterval = defaultS3UploadInterval
+	}
+	if cfg.ChunkSize <= 0 {
+		cfg.ChunkSize = defaultS3ChunkSize
+	}
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+	return &S3LogStore{
+		cfg:      cfg,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// chunkPrefix 返回日志 ID 对应的分块对象 key 前缀
+func (s *S3LogStore) chunkPrefix(logID int64) string {
+	return s.cfg.Prefix + logFileName(logID) + "/"
+}
+
+// s3Chunk 日志分块对象
+type s3Chunk struct {
+	key       string
+	startLine int // 分块第一行的行号
+}
+
+// listChunks 按行号顺序列出日志的所有分块
+func (s *S3LogStore) listChunks(logID int64) ([]s3Chunk, error) {
+	prefix := s.chunkPrefix(logID)
+	objects, err := s.listObjects(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([]s3Chunk, 0, len(objects))
+	for _, obj := range objects {
+		startLine, err := strconv.Atoi(strings.TrimPrefix(obj.Key, prefix))
+		if err != nil || startLine < 0 {
+			continue
+		}
+		chunks = append(chunks, s3Chunk{key: obj.Key, startLine: startLine})
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].startLine < chunks[j].startLine
+	})
+	return chunks, nil
+}
+
+// OpenWriter 打开日志写入端
+// 已存在的日志只下载最后一个分块以确定后续分块的起始行号
+func (s *S3LogStore) OpenWriter(logID int64) (LogSink, error) {
+	chunks, err := s.listChunks(logID)
+	if err != nil {
+		return nil, err
+	}
+
+	nextLine := 0
+	if len(chunks) > 0 {
+		last := chunks[len(chunks)-1]
+		body, err := s.getObject(last.key)
+		if err != nil {
+			return nil, err
+		}
+		nextLine = last.startLine + countLines(body)
+	}
+	return newS3Sink(s, s.chunkPrefix(logID), nextLine), nil
+}
+
+// ReadPage 分页读取日志
+// 从包含 fromLineNum 的分块开始按需依次下载，读够 pageSize 行后停止
+func (s *S3LogStore) ReadPage(logID int64, fromLineNum int, pageSize int) (*LogPage, error) {
+	chunks, err := s.listChunks(logID)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrLogNotFound, logID)
+	}
+
+	first := 0
+	for i, chunk := range chunks {
+		if chunk.startLine <= fromLineNum {
+			first = i
+		}
+	}
+	base := chunks[first].startLine
+
+	r := &s3ChunkReader{store: s, chunks: chunks[first:]}
+	defer r.Close()
+
+	page, err := readLogPage(r, fromLineNum-base, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	page.ToLineNum += base
+	return page, nil
+}
+
+// Delete 删除日志的所有分块对象
+func (s *S3LogStore) Delete(logID int64) error {
+	chunks, err := s.listChunks(logID)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		if err := s.deleteObject(chunk.key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// s3Object 对象基本信息
+type s3Object struct {
+	Key          string    `xml:"Key"`
+	LastModified time.Time `xml:"LastModified"`
+	Size         int64     `xml:"Size"`
+}
+
+// s3ListResult ListObjectsV2 响应
+type s3ListResult struct {
+	Contents              []s3Object `xml:"Contents"`
+	IsTruncated           bool       `xml:"IsTruncated"`
+	NextContinuationToken string     `xml:"NextContinuationToken"`
+}
+
+// List 列出前缀下的所有日志，按日志 ID 汇总各分块的大小和最后修改时间
+func (s *S3LogStore) List() ([]LogInfo, error) {
+	objects, err := s.listObjects(s.cfg.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []LogInfo
+	index := make(map[int64]int)
+	for _, obj := range objects {
+		name, _, ok := strings.Cut(strings.TrimPrefix(obj.Key, s.cfg.Prefix), "/")
+		if !ok {
+			continue
+		}
+		logID, ok := parseLogFileName(name)
+		if !ok {
+			continue
+		}
+		i, exists := index[logID]
+		if !exists {
+			i = len(infos)
+			index[logID] = i
+			infos = append(infos, LogInfo{LogID: logID})
+		}
+		infos[i].Size += obj.Size
+		if obj.LastModified.After(infos[i].ModTime) {
+			infos[i].ModTime = obj.LastModified
+		}
+	}
+	return infos, nil
+}
+
+// listObjects 列出指定前缀下的所有对象
+func (s *S3LogStore) listObjects(prefix string) ([]s3Object, error) {
+	var objects []s3Object
+	token := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			err := s.responseError("list", resp)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode s3 list response: %w", err)
+		}
+		objects = append(objects, result.Contents...)
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// openObject 打开对象内容的读取流，调用方负责关闭
+func (s *S3LogStore) openObject(key string) (io.ReadCloser, error) {
+	resp, err := s.do(http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrLogNotFound, key)
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := s.responseError("get", resp)
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// getObject 下载对象内容
+func (s *S3LogStore) getObject(key string) ([]byte, error) {
+	body, err := s.openObject(key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read s3 object: %w", err)
+	}
+	return data, nil
+}
+
+// putObject 上传对象内容
+func (s *S3LogStore) putObject(key string, body []byte) error {
+	resp, err := s.do(http.MethodPut, key, nil, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s.responseError("put", resp)
+	}
+	return nil
+}
+
+// deleteObject 删除对象，对象不存在时视为成功
+func (s *S3LogStore) deleteObject(key string) error {
+	resp, err := s.do(http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s.responseError("delete", resp)
+	}
+	return nil
+}
+
+// responseError 根据响应生成错误信息
+func (s *S3LogStore) responseError(op string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 %s failed with status %d: %s", op, resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// do 发送签名后的 S3 请求
+func (s *S3LogStore) do(method, key string, query url.Values, body []byte) (*http.Response, error) {
+	u := *s.endpoint
+	if s.cfg.UsePathStyle {
+		u.Path = s.endpoint.Path + "/" + s.cfg.Bucket
+		if key != "" {
+			u.Path += "/" + key
+		}
+	} else {
+		u.Host = s.cfg.Bucket + "." + s.endpoint.Host
+		u.Path = s.endpoint.Path + "/" + key
+	}
+	u.RawPath = awsURIEncode(u.Path, false)
+	if query != nil {
+		u.RawQuery = awsCanonicalQuery(query)
+	}
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 request: %w", err)
+	}
+	req.ContentLength = int64(len(body))
+	if method == http.MethodPut {
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	s.sign(req, body, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// sign 使用 AWS Signature Version 4 签名请求
+func (s *S3LogStore) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.UTC().Format("20060102T150405Z")
+	dateStamp := now.UTC().Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+	if s.cfg.AccessKeyID == "" {
+		// 未配置凭证时发送匿名请求
+		return
+	}
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if s.cfg.SessionToken != "" {
+		headers["x-amz-security-token"] = s.cfg.SessionToken
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := dateStamp + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), dateStamp)
+	signingKey = hmacSHA256(signingKey, s.cfg.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// s3ChunkReader 按顺序读取多个分块对象，读到某个分块时才下载
+type s3ChunkReader struct {
+	store  *S3LogStore
+	chunks []s3Chunk
+	cur    io.ReadCloser
+}
+
+// Read 读取当前分块，读完后打开下一个分块
+func (r *s3ChunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.cur == nil {
+			if len(r.chunks) == 0 {
+				return 0, io.EOF
+			}
+			body, err := r.store.openObject(r.chunks[0].key)
+			if err != nil {
+				return 0, err
+			}
+			r.cur = body
+			r.chunks = r.chunks[1:]
+		}
+
+		n, err := r.cur.Read(p)
+		if err == io.EOF {
+			r.cur.Close()
+			r.cur = nil
+			err = nil
+		}
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+// Close 关闭正在读取的分块
+func (r *s3ChunkReader) Close() error {
+	if r.cur == nil {
+		return nil
+	}
+	err := r.cur.Close()
+	r.cur = nil
+	return err
+}
+
+// s3Sink S3 日志写入端
+// 新写入的内容缓存在内存中，由后台 goroutine 上传为新的分块对象；
+// 除 Close 外只上传完整的行，保证每个分块都以换行符结尾
+type s3Sink struct {
+	store  *S3LogStore
+	prefix string // 分块对象 key 前缀
+
+	mu       sync.Mutex
+	pending  bytes.Buffer // 尚未上传的内容
+	nextLine int          // 下一个分块的起始行号
+	err      error        // 最近一次上传错误
+	closed   bool
+
+	uploadMu sync.Mutex // 保证分块按顺序上传
+	kick     chan struct{}
+	done     chan struct{}
+	stopped  chan struct{}
+}
+
+// newS3Sink 创建 S3 日志写入端并启动后台上传
+func newS3Sink(store *S3LogStore, prefix string, nextLine int) *s3Sink {
+	w := &s3Sink{
+		store:    store,
+		prefix:   prefix,
+		nextLine: nextLine,
+		kick:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go w.uploadLoop()
+	return w
+}
+
+// Write 追加到内存缓存，缓存达到分块大小时触发上传
+func (w *s3Sink) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, fmt.Errorf("s3 log sink is closed")
+	}
+	n, err := w.pending.Write(p)
+	if w.pending.Len() >= w.store.cfg.ChunkSize {
+		w.trigger()
+	}
+	return n, err
+}
+
+// Sync 触发一次后台上传，返回最近一次上传的错误
+func (w *s3Sink) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.trigger()
+	return w.err
+}
+
+// Close 停止后台上传，并同步上传剩余内容
+func (w *s3Sink) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.done)
+	<-w.stopped
+	return w.upload(true)
+}
+
+// trigger 通知后台上传（调用方需持有锁）
+func (w *s3Sink) trigger() {
+	select {
+	case w.kick <- struct{}{}:
+	default:
+	}
+}
+
+// uploadLoop 按上传间隔或收到通知时上传新内容
+// 上传失败时内容保留在缓存中，下次上传时重试
+func (w *s3Sink) uploadLoop() {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(w.store.cfg.UploadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		case <-w.kick:
+		}
+		_ = w.upload(false)
+	}
+}
+
+// upload 将缓存中的内容上传为一个新的分块
+// final 为 false 时只上传到最后一个换行符为止；上传期间不持有写入锁，不阻塞写入
+func (w *s3Sink) upload(final bool) error {
+	w.uploadMu.Lock()
+	defer w.uploadMu.Unlock()
+
+	w.mu.Lock()
+	data := w.pending.Bytes()
+	if !final {
+		data = data[:bytes.LastIndexByte(data, '\n')+1]
+	}
+	if len(data) == 0 {
+		w.mu.Unlock()
+		return nil
+	}
+	chunk := bytes.Clone(data)
+	key := w.prefix + fmt.Sprintf(s3ChunkNameFormat, w.nextLine)
+	w.mu.Unlock()
+
+	err := w.store.putObject(key, chunk)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.err = err
+	if err != nil {
+		return err
+	}
+	w.pending.Next(len(chunk))
+	w.nextLine += countLines(chunk)
+	return nil
+}
+
+// countLines 统计内容的行数，末尾不完整的行也计为一行
+func countLines(data []byte) int {
+	n := bytes.Count(data, []byte{'\n'})
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+// sha256Hex 计算 SHA256 并返回十六进制字符串
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 计算 HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// awsURIEncode 按 SigV4 规则进行 URI 编码
+// 仅保留 RFC 3986 非保留字符，encodeSlash 为 false 时保留路径分隔符
+func awsURIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// awsCanonicalQuery 生成按键排序且按 SigV4 规则编码的查询字符串
+func awsCanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, awsURIEncode(k, true)+"="+awsURIEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
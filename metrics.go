@@ -0,0 +1,99 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 执行器扩展指标，与 framework-metrics 一样仅在 metrics.IsEnabled() 时记录
+var (
+	// xxlJobDuplicateTotal 幂等中间件拦截的重复执行次数
+	xxlJobDuplicateTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xxljob_task_duplicates_total",
+			Help: "Total number of duplicate XXL-JOB task executions short-circuited by the idempotency guard",
+		},
+		[]string{"task_name", "status"},
+	)
+
+	// xxlJobRunning 正在执行的任务数
+	xxlJobRunning = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xxljob_tasks_running",
+			Help: "Number of XXL-JOB tasks currently running",
+		},
+		[]string{"task_name"},
+	)
+
+	// xxlJobWaiting 等待并发槽位的任务数（队列深度）
+	xxlJobWaiting = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xxljob_tasks_waiting",
+			Help: "Number of XXL-JOB tasks waiting for a concurrency slot",
+		},
+		[]string{"task_name"},
+	)
+
+	// xxlJobRejectedTotal 因并发上限被拒绝的执行次数
+	xxlJobRejectedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xxljob_tasks_rejected_total",
+			Help: "Total number of XXL-JOB task executions rejected by concurrency limits",
+		},
+		[]string{"task_name"},
+	)
+
+	// xxlJobRetriesTotal 重试次数
+	xxlJobRetriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xxljob_task_retries_total",
+			Help: "Total number of XXL-JOB task retry attempts",
+		},
+		[]string{"task_name"},
+	)
+
+	// xxlJobAttempts 每次执行的尝试次数
+	xxlJobAttempts = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "xxljob_task_attempts",
+			Help:    "Number of attempts per XXL-JOB task execution",
+			Buckets: []float64{1, 2, 3, 4, 5, 7, 10, 15, 20},
+		},
+		[]string{"task_name"},
+	)
+
+	// xxlJobCircuits 熔断器各状态的熔断键数量
+	// 熔断键可以由 CircuitKeyFunc 按参数生成，取值不受限制，不作为指标标签；各键的状态见健康状态
+	xxlJobCircuits = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "xxljob_circuit_breaker_circuits",
+			Help: "Number of XXL-JOB circuit breaker keys in each state (closed, open, half_open)",
+		},
+		[]string{"breaker", "state"},
+	)
+
+	// xxlJobCircuitRejectedTotal 熔断器拒绝的执行次数
+	xxlJobCircuitRejectedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "xxljob_circuit_breaker_rejected_total",
+			Help: "Total number of XXL-JOB task executions rejected by an open circuit breaker",
+		},
+		[]string{"breaker"},
+	)
+)
@@ -33,17 +33,6 @@ func Chain(middlewares ...Middleware) Middleware {
 	}
 }
 
-// applyMiddlewares 应用中间件链到任务处理器
-func applyMiddlewares(handler TaskHandler, middlewares []Middleware) TaskHandler {
-	if len(middlewares) == 0 {
-		return handler
-	}
-
-	// 构建中间件链
-	chain := Chain(middlewares...)
-	return chain(handler)
-}
-
 // RecoveryMiddleware 恢复中间件
 // 捕获 panic 并转换为 error
 func RecoveryMiddleware() Middleware {
@@ -98,33 +87,10 @@ func TimeoutMiddleware(timeout time.Duration) Middleware {
 
 // RetryMiddleware 重试中间件
 // 在任务失败时自动重试（注意：XXL-JOB 本身也支持重试，此中间件用于客户端重试）
+// 退避时间从 backoff 开始指数增长，需要抖动、退避上限或错误分类时使用 RetryPolicyMiddleware
 func RetryMiddleware(maxRetries int, backoff time.Duration) Middleware {
-	return func(next TaskHandler) TaskHandler {
-		return func(ctx context.Context, param string) error {
-			var lastErr error
-			for i := 0; i <= maxRetries; i++ {
-				if i > 0 {
-					// 等待后重试
-					select {
-					case <-ctx.Done():
-						return ctx.Err()
-					case <-time.After(backoff):
-					}
-					backoff *= 2 // 指数退避
-				}
-
-				err := next(ctx, param)
-				if err == nil {
-					return nil
-				}
-				lastErr = err
-
-				// 检查上下文是否已取消
-				if ctx.Err() != nil {
-					return ctx.Err()
-				}
-			}
-			return lastErr
-		}
-	}
+	return RetryPolicyMiddleware(RetryPolicy{
+		MaxRetries:     maxRetries,
+		InitialBackoff: backoff,
+	})
 }
@@ -0,0 +1,156 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import "path"
+
+// TaskMatcher 根据任务信息判断是否启用中间件
+type TaskMatcher func(info *TaskInfo) bool
+
+// MatchName 任务名称匹配任一 glob 模式时为真（语法同 path.Match，例如 billing.*）
+func MatchName(patterns ...string) TaskMatcher {
+	return func(info *TaskInfo) bool {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, info.Name); ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchTags 任务带有全部指定标签时为真
+func MatchTags(tags ...string) TaskMatcher {
+	return func(info *TaskInfo) bool {
+		for _, tag := range tags {
+			if !info.HasTag(tag) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchAnyTag 任务带有任一指定标签时为真
+func MatchAnyTag(tags ...string) TaskMatcher {
+	return func(info *TaskInfo) bool {
+		for _, tag := range tags {
+			if info.HasTag(tag) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchAll 全部匹配器为真时为真
+func MatchAll(matchers ...TaskMatcher) TaskMatcher {
+	return func(info *TaskInfo) bool {
+		for _, m := range matchers {
+			if !m(info) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchAny 任一匹配器为真时为真
+func MatchAny(matchers ...TaskMatcher) TaskMatcher {
+	return func(info *TaskInfo) bool {
+		for _, m := range matchers {
+			if m(info) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not 对匹配器取反
+func Not(matcher TaskMatcher) TaskMatcher {
+	return func(info *TaskInfo) bool {
+		return !matcher(info)
+	}
+}
+
+// TaskMiddleware 任务中间件，组装任务处理器时根据任务信息决定中间件链
+// Middleware 以及 When、Named 返回的中间件都实现该接口
+type TaskMiddleware interface {
+	// WrapTask 为 info 描述的任务组装中间件，返回组装后的处理器和生效的中间件名称（由外到内）；
+	// 未启用时返回 next 和空名称
+	WrapTask(info *TaskInfo, next TaskHandler) (TaskHandler, []string)
+}
+
+// WrapTask 组装普通中间件，未命名的中间件记为 anonymous
+func (m Middleware) WrapTask(_ *TaskInfo, next TaskHandler) (TaskHandler, []string) {
+	return m(next), []string{"anonymous"}
+}
+
+// conditionalMiddleware When 返回的条件中间件
+type conditionalMiddleware struct {
+	matcher    TaskMatcher
+	middleware TaskMiddleware
+}
+
+// When 条件中间件：仅对匹配的任务启用 middleware，注册任务时根据 TaskInfo 决定是否加入中间件链
+func When(matcher TaskMatcher, middleware TaskMiddleware) TaskMiddleware {
+	return &conditionalMiddleware{matcher: matcher, middleware: middleware}
+}
+
+// WrapTask 任务匹配时组装内部中间件，未命名的普通中间件记为 conditional
+func (c *conditionalMiddleware) WrapTask(info *TaskInfo, next TaskHandler) (TaskHandler, []string) {
+	if !c.matcher(info) {
+		return next, nil
+	}
+	handler, names := c.middleware.WrapTask(info, next)
+	if _, plain := c.middleware.(Middleware); plain {
+		names = []string{"conditional"}
+	}
+	return handler, names
+}
+
+// namedMiddleware Named 返回的命名中间件
+type namedMiddleware struct {
+	name       string
+	middleware TaskMiddleware
+}
+
+// Named 为中间件命名，名称会出现在 TaskInfo.EffectiveMiddlewares 中
+func Named(name string, middleware TaskMiddleware) TaskMiddleware {
+	return &namedMiddleware{name: name, middleware: middleware}
+}
+
+// WrapTask 组装内部中间件，内部的条件中间件全部未启用时视为未启用
+func (n *namedMiddleware) WrapTask(info *TaskInfo, next TaskHandler) (TaskHandler, []string) {
+	handler, names := n.middleware.WrapTask(info, next)
+	if len(names) == 0 {
+		return handler, nil
+	}
+	return handler, []string{n.name}
+}
+
+// buildChain 为 info 描述的任务组装中间件链，返回生效的中间件名称（由外到内）
+func buildChain(info *TaskInfo, handler TaskHandler, middlewares []TaskMiddleware) (TaskHandler, []string) {
+	var names []string
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		var applied []string
+		handler, applied = middlewares[i].WrapTask(info, handler)
+		names = append(applied, names...)
+	}
+	return handler, names
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordMiddleware 执行时记录名称的中间件
+func recordMiddleware(name string, calls *[]string) Middleware {
+	return func(next TaskHandler) TaskHandler {
+		return func(ctx context.Context, param string) error {
+			*calls = append(*calls, name)
+			return next(ctx, param)
+		}
+	}
+}
+
+func TestBuildHandler(t *testing.T) {
+	var calls []string
+	billing := When(MatchTags("billing"), Named("audit", recordMiddleware("audit", &calls)))
+	reports := When(MatchName("report.*"), recordMiddleware("report", &calls))
+
+	tests := []struct {
+		name      string
+		info      TaskInfo
+		global    []TaskMiddleware
+		wantChain []string
+		wantCalls []string
+	}{
+		{
+			name:      "conditional matched",
+			info:      TaskInfo{Name: "charge", Tags: []string{"billing"}},
+			global:    []TaskMiddleware{Named("recovery", RecoveryMiddleware()), billing},
+			wantChain: []string{"recovery", "audit"},
+			wantCalls: []string{"audit"},
+		},
+		{
+			name:      "conditional skipped",
+			info:      TaskInfo{Name: "cleanup"},
+			global:    []TaskMiddleware{billing, reports},
+			wantChain: nil,
+			wantCalls: nil,
+		},
+		{
+			name:      "unnamed conditional",
+			info:      TaskInfo{Name: "report.daily"},
+			global:    []TaskMiddleware{billing, reports},
+			wantChain: []string{"conditional"},
+			wantCalls: []string{"report"},
+		},
+		{
+			name:      "named around skipped conditional",
+			info:      TaskInfo{Name: "cleanup"},
+			global:    []TaskMiddleware{Named("billing", billing)},
+			wantChain: nil,
+		},
+		{
+			name: "task middlewares inside global and timeout",
+			info: TaskInfo{
+				Name:        "report.daily",
+				Timeout:     time.Minute,
+				Middlewares: []TaskMiddleware{recordMiddleware("task", &calls), reports},
+			},
+			global:    []TaskMiddleware{Named("outer", recordMiddleware("outer", &calls))},
+			wantChain: []string{"outer", "timeout", "anonymous", "conditional"},
+			wantCalls: []string{"outer", "task", "report"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			info := tt.info
+			info.Handler = func(context.Context, string) error { return nil }
+
+			handler := info.buildHandler(tt.global)
+			if !reflect.DeepEqual(info.EffectiveMiddlewares, tt.wantChain) {
+				t.Errorf("EffectiveMiddlewares = %v, want %v", info.EffectiveMiddlewares, tt.wantChain)
+			}
+			if err := handler(context.Background(), ""); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// 并发注册的任务各自按自己的任务信息组装条件中间件
+func TestBuildHandlerConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	middleware := When(MatchTags("even"), Named("even", RecoveryMiddleware()))
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			info := &TaskInfo{Name: fmt.Sprintf("task-%d", i), Handler: func(context.Context, string) error { return nil }}
+			if i%2 == 0 {
+				info.Tags = []string{"even"}
+			}
+			info.buildHandler([]TaskMiddleware{middleware})
+			if got := len(info.EffectiveMiddlewares) == 1; got != (i%2 == 0) {
+				t.Errorf("%s: EffectiveMiddlewares = %v", info.Name, info.EffectiveMiddlewares)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
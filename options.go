@@ -17,7 +17,10 @@
 package xxljob
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"time"
 )
 
 // Config XXL-JOB 配置结构体（用于从配置文件创建）
@@ -30,11 +33,39 @@ type Config struct {
 	RegistryKey      string `yaml:"registry_key" env:"XXL_JOB_REGISTRY_KEY" required:"true"`
 	LogPath          string `yaml:"log_path" env:"XXL_JOB_LOG_PATH" default:"./logs/xxl-job"`
 	LogRetentionDays int    `yaml:"log_retention_days" env:"XXL_JOB_LOG_RETENTION_DAYS" default:"30"`
-	EnableTrace      bool   `yaml:"enable_trace" env:"XXL_JOB_ENABLE_TRACE" default:"true"`
-	QuietMode        bool   `yaml:"quiet_mode" env:"XXL_JOB_QUIET_MODE" default:"false"`
-}
-
-// Validate 验证配置
+	// StateFile 本地状态文件（保存暂停的任务），为空时状态仅保存在内存中
+	StateFile        string        `yaml:"state_file" env:"XXL_JOB_STATE_FILE"`
+	LogSyncPolicy    string        `yaml:"log_sync_policy" env:"XXL_JOB_LOG_SYNC_POLICY" default:"interval"`
+	LogFlushInterval time.Duration `yaml:"log_flush_interval" env:"XXL_JOB_LOG_FLUSH_INTERVAL" default:"1s"`
+	LogBufferSize    int           `yaml:"log_buffer_size" env:"XXL_JOB_LOG_BUFFER_SIZE" default:"32768"`
+	LogFormat        string        `yaml:"log_format" env:"XXL_JOB_LOG_FORMAT" default:"text"`
+	LogLevel         string        `yaml:"log_level" env:"XXL_JOB_LOG_LEVEL" default:"info"`
+	// TaskLogLevels 按任务名称覆盖最低日志级别
+	TaskLogLevels map[string]string `yaml:"task_log_levels"`
+	// LogStore 日志存储类型：file、memory、s3；为空时有 LogPath 则使用 file，否则使用 memory
+	LogStore string           `yaml:"log_store" env:"XXL_JOB_LOG_STORE"`
+	LogS3    S3LogStoreConfig `yaml:"log_s3"`
+	// Redaction 任务参数脱敏配置（默认启用）
+	Redaction        RedactionConfig `yaml:"redaction"`
+	EnableTrace      bool            `yaml:"enable_trace" env:"XXL_JOB_ENABLE_TRACE" default:"true"`
+	TracePropagation string          `yaml:"trace_propagation" env:"XXL_JOB_TRACE_PROPAGATION" default:"parent"` // parent、link、none
+	// TaskTraces 按任务名称覆盖追踪配置（采样比例、失败补记、记录的属性），未配置的任务跟随 EnableTrace
+	TaskTraces map[string]TaskTraceConfig `yaml:"task_traces"`
+	// Concurrency 执行器级并发限制，TaskConcurrency 按任务名称设置任务级并发限制
+	Concurrency     ConcurrencyConfig            `yaml:"concurrency"`
+	TaskConcurrency map[string]ConcurrencyConfig `yaml:"task_concurrency"`
+	// TraceLogEvents 任务日志行记录为 span 事件的配置（默认启用）
+	TraceLogEvents TraceLogEventsConfig `yaml:"trace_log_events"`
+	// History 执行历史配置（默认启用，仅保存在内存中）
+	History HistoryConfig `yaml:"history"`
+	// Fallback 调度中心不可达时的本地兜底调度（默认关闭）
+	Fallback FallbackConfig `yaml:"fallback"`
+	// Admin 调度中心管理接口账号（用于 AdminClient 和命令行工具）
+	Admin     AdminConfig `yaml:"admin"`
+	QuietMode bool        `yaml:"quiet_mode" env:"XXL_JOB_QUIET_MODE" default:"false"`
+}
+
+// Validate 验证配置，返回所有不合法的配置项
 func (c *Config) Validate() error {
 	if c == nil {
 		return fmt.Errorf("xxl-job config cannot be nil")
@@ -42,16 +73,80 @@ func (c *Config) Validate() error {
 	if !c.Enabled {
 		return nil // 如果未启用，不需要验证
 	}
-	if c.ServerAddr == "" {
-		return fmt.Errorf("xxl-job server_addr is required")
+	// 按 required 标签检查必填项，所有错误合并后一次返回
+	errs := requiredFieldErrors(reflect.ValueOf(c).Elem(), "")
+	if c.ExecutorPort == "" {
+		errs = append(errs, fmt.Errorf("xxl-job executor_port is required"))
 	}
-	if c.RegistryKey == "" {
-		return fmt.Errorf("xxl-job registry_key is required")
+	if _, err := ParseLogSyncPolicy(c.LogSyncPolicy); err != nil {
+		errs = append(errs, fmt.Errorf("xxl-job log_sync_policy is invalid: %w", err))
 	}
-	if c.ExecutorPort == "" {
-		return fmt.Errorf("xxl-job executor_port is required")
+	if _, err := ParseLogFormat(c.LogFormat); err != nil {
+		errs = append(errs, fmt.Errorf("xxl-job log_format is invalid: %w", err))
+	}
+	if _, err := ParseLogLevel(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("xxl-job log_level is invalid: %w", err))
+	}
+	for task, level := range c.TaskLogLevels {
+		if _, err := ParseLogLevel(level); err != nil {
+			errs = append(errs, fmt.Errorf("xxl-job task_log_levels[%s] is invalid: %w", task, err))
+		}
+	}
+	switch c.LogStore {
+	case "", "file", "memory":
+	case "s3":
+		if err := c.LogS3.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("xxl-job log_s3 is invalid: %w", err))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("xxl-job log_store is invalid: %s", c.LogStore))
+	}
+	if _, err := NewRedactor(c.Redaction); err != nil {
+		errs = append(errs, fmt.Errorf("xxl-job redaction is invalid: %w", err))
+	}
+	if _, err := ParseTracePropagationMode(c.TracePropagation); err != nil {
+		errs = append(errs, fmt.Errorf("xxl-job trace_propagation is invalid: %w", err))
+	}
+	for task, tracing := range c.TaskTraces {
+		if err := tracing.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("xxl-job task_traces[%s] is invalid: %w", task, err))
+		}
+	}
+	if err := c.Concurrency.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("xxl-job concurrency is invalid: %w", err))
+	}
+	for task, limit := range c.TaskConcurrency {
+		if err := limit.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("xxl-job task_concurrency[%s] is invalid: %w", task, err))
+		}
+	}
+	if err := c.TraceLogEvents.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("xxl-job trace_log_events is invalid: %w", err))
+	}
+	if err := c.History.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("xxl-job history is invalid: %w", err))
+	}
+	if err := c.Fallback.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("xxl-job fallback is invalid: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// buildLogStore 根据配置创建日志存储，返回 nil 表示由执行器根据 LogPath 自动选择
+func (c *Config) buildLogStore() (LogStore, error) {
+	switch c.LogStore {
+	case "memory":
+		return NewMemoryLogStore(0, 0), nil
+	case "s3":
+		return NewS3LogStore(c.LogS3)
+	case "file":
+		if c.LogPath == "" {
+			return nil, fmt.Errorf("log_path is required for file log store")
+		}
+		return NewFileLogStore(c.LogPath)
+	default:
+		return nil, nil
 	}
-	return nil
 }
 
 // ToOptions 转换为 executorOptions
@@ -70,9 +165,19 @@ func (c *Config) ToOptions() (*executorOptions, error) {
 	opts.executorPort = c.ExecutorPort
 	opts.registryKey = c.RegistryKey
 	opts.logPath = c.LogPath
-	opts.logRetentionDays = c.LogRetentionDays
-	opts.enableTrace = c.EnableTrace
-	opts.quietMode = c.QuietMode
+	opts.stateFile = c.StateFile
+	logStore, err := c.buildLogStore()
+	if err != nil {
+		return nil, fmt.Errorf("invalid log store: %w", err)
+	}
+	opts.logStore = logStore
+	opts.history = c.History
+	opts.fallback = c.Fallback
+	opts.concurrency = c.Concurrency
+	for task, limit := range c.TaskConcurrency {
+		opts.taskConcurrency[task] = limit
+	}
+	c.applyReloadable(opts)
 
 	if err := opts.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid options: %w", err)
@@ -81,6 +186,35 @@ func (c *Config) ToOptions() (*executorOptions, error) {
 	return opts, nil
 }
 
+// applyReloadable 设置执行器运行期间可以修改的选项（ToOptions 和 ApplyConfig 共用），调用前需验证配置
+func (c *Config) applyReloadable(opts *executorOptions) {
+	opts.logRetentionDays = c.LogRetentionDays
+	opts.logSyncPolicy, _ = ParseLogSyncPolicy(c.LogSyncPolicy)
+	opts.logFlushInterval = defaultLogFlushInterval
+	if c.LogFlushInterval > 0 {
+		opts.logFlushInterval = c.LogFlushInterval
+	}
+	opts.logBufferSize = defaultLogBufferSize
+	if c.LogBufferSize > 0 {
+		opts.logBufferSize = c.LogBufferSize
+	}
+	opts.logFormat, _ = ParseLogFormat(c.LogFormat)
+	opts.logLevel, _ = ParseLogLevel(c.LogLevel)
+	opts.taskLogLevels = make(map[string]LogLevel, len(c.TaskLogLevels))
+	for task, level := range c.TaskLogLevels {
+		opts.taskLogLevels[task], _ = ParseLogLevel(level)
+	}
+	opts.redactor, _ = NewRedactor(c.Redaction)
+	opts.enableTrace = c.EnableTrace
+	opts.tracePropagation, _ = ParseTracePropagationMode(c.TracePropagation)
+	opts.taskTraces = make(map[string]TaskTraceConfig, len(c.TaskTraces))
+	for task, tracing := range c.TaskTraces {
+		opts.taskTraces[task] = tracing
+	}
+	opts.traceLogEvents = c.TraceLogEvents
+	opts.quietMode = c.QuietMode
+}
+
 // executorOptions XXL-JOB 执行器选项（内部使用）
 // 使用 Builder 模式构建
 type executorOptions struct {
@@ -91,9 +225,26 @@ type executorOptions struct {
 	registryKey      string
 	logPath          string
 	logRetentionDays int
+	stateFile        string        // 本地状态文件，为空时不持久化
+	logSyncPolicy    LogSyncPolicy // 日志落盘策略
+	logFlushInterval time.Duration // 日志缓冲刷新间隔
+	logBufferSize    int           // 日志缓冲区大小（字节）
+	logFormat        LogFormat     // 任务日志行格式
+	logLevel         LogLevel      // 任务日志最低级别
+	taskLogLevels    map[string]LogLevel
+	logStore         LogStore  // 日志存储，为空时根据 logPath 自动选择
+	redactor         *Redactor // 参数脱敏器，为空时不脱敏
 	enableTrace      bool
-	quietMode        bool // 静默模式：不输出心跳/注册日志
-	middlewares      []Middleware
+	tracePropagation TracePropagationMode         // 上游追踪上下文关联方式
+	taskTraces       map[string]TaskTraceConfig   // 按任务覆盖的追踪配置
+	traceLogEvents   TraceLogEventsConfig         // 日志行 span 事件配置
+	history          HistoryConfig                // 执行历史配置
+	fallback         FallbackConfig               // 本地兜底调度配置
+	fallbackLocker   Locker                       // 兜底调度的分布式锁，为空时不加锁
+	concurrency      ConcurrencyConfig            // 执行器级并发限制
+	taskConcurrency  map[string]ConcurrencyConfig // 任务级并发限制
+	quietMode        bool                         // 静默模式：不输出心跳/注册日志
+	middlewares      []TaskMiddleware
 }
 
 // Option 配置选项函数类型
@@ -104,9 +255,21 @@ func NewOptions() *executorOptions {
 	return &executorOptions{
 		executorPort:     "9999",
 		logRetentionDays: 30,
+		logSyncPolicy:    SyncInterval,
+		logFlushInterval: defaultLogFlushInterval,
+		logBufferSize:    defaultLogBufferSize,
+		logFormat:        LogFormatText,
+		logLevel:         LogLevelInfo,
+		taskLogLevels:    make(map[string]LogLevel),
+		taskTraces:       make(map[string]TaskTraceConfig),
+		traceLogEvents:   DefaultTraceLogEventsConfig(),
+		history:          DefaultHistoryConfig(),
+		fallback:         DefaultFallbackConfig(),
+		taskConcurrency:  make(map[string]ConcurrencyConfig),
+		redactor:         defaultRedactor(), // 默认启用参数脱敏
 		enableTrace:      false,
 		quietMode:        false, // 默认输出心跳日志
-		middlewares:      make([]Middleware, 0),
+		middlewares:      make([]TaskMiddleware, 0),
 	}
 }
 
@@ -152,6 +315,13 @@ func WithLogPath(path string) Option {
 	}
 }
 
+// WithStateFile 设置本地状态文件（保存暂停的任务，重启后恢复）
+func WithStateFile(path string) Option {
+	return func(o *executorOptions) {
+		o.stateFile = path
+	}
+}
+
 // WithLogRetentionDays 设置日志保留天数
 func WithLogRetentionDays(days int) Option {
 	return func(o *executorOptions) {
@@ -159,6 +329,62 @@ func WithLogRetentionDays(days int) Option {
 	}
 }
 
+// WithLogSyncPolicy 设置日志落盘策略
+func WithLogSyncPolicy(policy LogSyncPolicy) Option {
+	return func(o *executorOptions) {
+		o.logSyncPolicy = policy
+	}
+}
+
+// WithLogFlushInterval 设置日志缓冲刷新间隔
+func WithLogFlushInterval(interval time.Duration) Option {
+	return func(o *executorOptions) {
+		o.logFlushInterval = interval
+	}
+}
+
+// WithLogBufferSize 设置日志缓冲区大小（字节）
+func WithLogBufferSize(size int) Option {
+	return func(o *executorOptions) {
+		o.logBufferSize = size
+	}
+}
+
+// WithLogFormat 设置任务日志行格式
+func WithLogFormat(format LogFormat) Option {
+	return func(o *executorOptions) {
+		o.logFormat = format
+	}
+}
+
+// WithLogLevel 设置任务日志最低级别
+func WithLogLevel(level LogLevel) Option {
+	return func(o *executorOptions) {
+		o.logLevel = level
+	}
+}
+
+// WithTaskLogLevel 为指定任务设置最低日志级别（覆盖全局级别）
+func WithTaskLogLevel(taskName string, level LogLevel) Option {
+	return func(o *executorOptions) {
+		o.taskLogLevels[taskName] = level
+	}
+}
+
+// WithLogStore 设置日志存储后端
+func WithLogStore(store LogStore) Option {
+	return func(o *executorOptions) {
+		o.logStore = store
+	}
+}
+
+// WithRedactor 设置参数脱敏器，传入 nil 表示关闭脱敏
+func WithRedactor(redactor *Redactor) Option {
+	return func(o *executorOptions) {
+		o.redactor = redactor
+	}
+}
+
 // WithTrace 启用/禁用追踪
 func WithTrace(enabled bool) Option {
 	return func(o *executorOptions) {
@@ -166,6 +392,62 @@ func WithTrace(enabled bool) Option {
 	}
 }
 
+// WithTracePropagation 设置上游追踪上下文关联方式
+func WithTracePropagation(mode TracePropagationMode) Option {
+	return func(o *executorOptions) {
+		o.tracePropagation = mode
+	}
+}
+
+// WithTaskTrace 为指定任务设置追踪配置（覆盖 EnableTrace）
+func WithTaskTrace(taskName string, cfg TaskTraceConfig) Option {
+	return func(o *executorOptions) {
+		o.taskTraces[taskName] = cfg
+	}
+}
+
+// WithTraceLogEvents 设置任务日志行记录为 span 事件的方式
+func WithTraceLogEvents(cfg TraceLogEventsConfig) Option {
+	return func(o *executorOptions) {
+		o.traceLogEvents = cfg
+	}
+}
+
+// WithHistory 设置执行历史的保留数量和持久化文件
+func WithHistory(cfg HistoryConfig) Option {
+	return func(o *executorOptions) {
+		o.history = cfg
+	}
+}
+
+// WithFallback 设置调度中心不可达时的本地兜底调度
+func WithFallback(cfg FallbackConfig) Option {
+	return func(o *executorOptions) {
+		o.fallback = cfg
+	}
+}
+
+// WithFallbackLocker 设置兜底调度使用的分布式锁，避免多个副本重复执行
+func WithFallbackLocker(locker Locker) Option {
+	return func(o *executorOptions) {
+		o.fallbackLocker = locker
+	}
+}
+
+// WithConcurrency 设置执行器级并发限制
+func WithConcurrency(cfg ConcurrencyConfig) Option {
+	return func(o *executorOptions) {
+		o.concurrency = cfg
+	}
+}
+
+// WithTaskConcurrency 为指定任务设置并发限制
+func WithTaskConcurrency(taskName string, cfg ConcurrencyConfig) Option {
+	return func(o *executorOptions) {
+		o.taskConcurrency[taskName] = cfg
+	}
+}
+
 // WithQuietMode 启用/禁用静默模式（不输出心跳/注册日志）
 func WithQuietMode(enabled bool) Option {
 	return func(o *executorOptions) {
@@ -174,14 +456,14 @@ func WithQuietMode(enabled bool) Option {
 }
 
 // WithMiddleware 添加中间件
-func WithMiddleware(middleware Middleware) Option {
+func WithMiddleware(middleware TaskMiddleware) Option {
 	return func(o *executorOptions) {
 		o.middlewares = append(o.middlewares, middleware)
 	}
 }
 
 // WithMiddlewares 批量添加中间件
-func WithMiddlewares(middlewares ...Middleware) Option {
+func WithMiddlewares(middlewares ...TaskMiddleware) Option {
 	return func(o *executorOptions) {
 		o.middlewares = append(o.middlewares, middlewares...)
 	}
@@ -198,6 +480,31 @@ func (o *executorOptions) Validate() error {
 	if o.executorPort == "" {
 		return fmt.Errorf("executor port is required")
 	}
+	if o.logSyncPolicy < SyncEveryLine || o.logSyncPolicy > SyncOnClose {
+		return fmt.Errorf("invalid log sync policy: %s", o.logSyncPolicy)
+	}
+	for task, tracing := range o.taskTraces {
+		if err := tracing.Validate(); err != nil {
+			return fmt.Errorf("invalid trace config for task %s: %w", task, err)
+		}
+	}
+	if err := o.traceLogEvents.Validate(); err != nil {
+		return fmt.Errorf("invalid trace log events config: %w", err)
+	}
+	if err := o.history.Validate(); err != nil {
+		return fmt.Errorf("invalid history config: %w", err)
+	}
+	if err := o.fallback.Validate(); err != nil {
+		return fmt.Errorf("invalid fallback config: %w", err)
+	}
+	if err := o.concurrency.Validate(); err != nil {
+		return fmt.Errorf("invalid concurrency config: %w", err)
+	}
+	for task, limit := range o.taskConcurrency {
+		if err := limit.Validate(); err != nil {
+			return fmt.Errorf("invalid concurrency config for task %s: %w", task, err)
+		}
+	}
 	return nil
 }
 
@@ -217,9 +524,70 @@ func NewFromConfig(cfg *Config) (Executor, error) {
 		ExecutorPort(cfg.ExecutorPort).
 		LogPath(cfg.LogPath).
 		LogRetentionDays(cfg.LogRetentionDays).
+		StateFile(cfg.StateFile).
 		Trace(cfg.EnableTrace).
 		QuietMode(cfg.QuietMode)
 
+	syncPolicy, err := ParseLogSyncPolicy(cfg.LogSyncPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log sync policy: %w", err)
+	}
+	builder = builder.LogSyncPolicy(syncPolicy)
+
+	logFormat, err := ParseLogFormat(cfg.LogFormat)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log format: %w", err)
+	}
+	logLevel, err := ParseLogLevel(cfg.LogLevel)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log level: %w", err)
+	}
+	builder = builder.LogFormat(logFormat).LogLevel(logLevel)
+	for task, name := range cfg.TaskLogLevels {
+		level, err := ParseLogLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level for task %s: %w", task, err)
+		}
+		builder = builder.TaskLogLevel(task, level)
+	}
+
+	logStore, err := cfg.buildLogStore()
+	if err != nil {
+		return nil, fmt.Errorf("invalid log store: %w", err)
+	}
+	if logStore != nil {
+		builder = builder.LogStore(logStore)
+	}
+
+	redactor, err := NewRedactor(cfg.Redaction)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redaction config: %w", err)
+	}
+	builder = builder.Redactor(redactor)
+
+	propagationMode, err := ParseTracePropagationMode(cfg.TracePropagation)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trace propagation mode: %w", err)
+	}
+	builder = builder.TracePropagation(propagationMode)
+
+	for task, tracing := range cfg.TaskTraces {
+		builder = builder.TaskTrace(task, tracing)
+	}
+	builder = builder.TraceLogEvents(cfg.TraceLogEvents)
+	builder = builder.History(cfg.History)
+	builder = builder.Fallback(cfg.Fallback)
+	builder = builder.Concurrency(cfg.Concurrency)
+	for task, limit := range cfg.TaskConcurrency {
+		builder = builder.TaskConcurrency(task, limit)
+	}
+	if cfg.LogFlushInterval > 0 {
+		builder = builder.LogFlushInterval(cfg.LogFlushInterval)
+	}
+	if cfg.LogBufferSize > 0 {
+		builder = builder.LogBufferSize(cfg.LogBufferSize)
+	}
+
 	if cfg.AccessToken != "" {
 		builder = builder.AccessToken(cfg.AccessToken)
 	}
@@ -227,7 +595,15 @@ func NewFromConfig(cfg *Config) (Executor, error) {
 		builder = builder.ExecutorIP(cfg.ExecutorIP)
 	}
 
-	return builder.Build()
+	exec, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+	// 记录创建时的配置，作为 ApplyConfig 比较变更的基准
+	if impl, ok := exec.(*executorImpl); ok {
+		impl.config = snapshotConfig(cfg)
+	}
+	return exec, nil
 }
 
 // OptionsBuilder 选项构建器（链式调用）
@@ -278,18 +654,120 @@ func (b *OptionsBuilder) LogPath(path string) *OptionsBuilder {
 	return b
 }
 
+// StateFile 设置本地状态文件（保存暂停的任务，重启后恢复）
+func (b *OptionsBuilder) StateFile(path string) *OptionsBuilder {
+	b.opts.stateFile = path
+	return b
+}
+
 // LogRetentionDays 设置日志保留天数
 func (b *OptionsBuilder) LogRetentionDays(days int) *OptionsBuilder {
 	b.opts.logRetentionDays = days
 	return b
 }
 
+// LogSyncPolicy 设置日志落盘策略
+func (b *OptionsBuilder) LogSyncPolicy(policy LogSyncPolicy) *OptionsBuilder {
+	b.opts.logSyncPolicy = policy
+	return b
+}
+
+// LogFlushInterval 设置日志缓冲刷新间隔
+func (b *OptionsBuilder) LogFlushInterval(interval time.Duration) *OptionsBuilder {
+	b.opts.logFlushInterval = interval
+	return b
+}
+
+// LogBufferSize 设置日志缓冲区大小（字节）
+func (b *OptionsBuilder) LogBufferSize(size int) *OptionsBuilder {
+	b.opts.logBufferSize = size
+	return b
+}
+
+// LogFormat 设置任务日志行格式
+func (b *OptionsBuilder) LogFormat(format LogFormat) *OptionsBuilder {
+	b.opts.logFormat = format
+	return b
+}
+
+// LogLevel 设置任务日志最低级别
+func (b *OptionsBuilder) LogLevel(level LogLevel) *OptionsBuilder {
+	b.opts.logLevel = level
+	return b
+}
+
+// TaskLogLevel 为指定任务设置最低日志级别
+func (b *OptionsBuilder) TaskLogLevel(taskName string, level LogLevel) *OptionsBuilder {
+	b.opts.taskLogLevels[taskName] = level
+	return b
+}
+
+// LogStore 设置日志存储后端
+func (b *OptionsBuilder) LogStore(store LogStore) *OptionsBuilder {
+	b.opts.logStore = store
+	return b
+}
+
+// Redactor 设置参数脱敏器，传入 nil 表示关闭脱敏
+func (b *OptionsBuilder) Redactor(redactor *Redactor) *OptionsBuilder {
+	b.opts.redactor = redactor
+	return b
+}
+
 // Trace 启用/禁用追踪
 func (b *OptionsBuilder) Trace(enabled bool) *OptionsBuilder {
 	b.opts.enableTrace = enabled
 	return b
 }
 
+// TracePropagation 设置上游追踪上下文关联方式
+func (b *OptionsBuilder) TracePropagation(mode TracePropagationMode) *OptionsBuilder {
+	b.opts.tracePropagation = mode
+	return b
+}
+
+// TaskTrace 为指定任务设置追踪配置
+func (b *OptionsBuilder) TaskTrace(taskName string, cfg TaskTraceConfig) *OptionsBuilder {
+	b.opts.taskTraces[taskName] = cfg
+	return b
+}
+
+// TraceLogEvents 设置任务日志行记录为 span 事件的方式
+func (b *OptionsBuilder) TraceLogEvents(cfg TraceLogEventsConfig) *OptionsBuilder {
+	b.opts.traceLogEvents = cfg
+	return b
+}
+
+// History 设置执行历史的保留数量和持久化文件
+func (b *OptionsBuilder) History(cfg HistoryConfig) *OptionsBuilder {
+	b.opts.history = cfg
+	return b
+}
+
+// Fallback 设置调度中心不可达时的本地兜底调度
+func (b *OptionsBuilder) Fallback(cfg FallbackConfig) *OptionsBuilder {
+	b.opts.fallback = cfg
+	return b
+}
+
+// FallbackLocker 设置兜底调度使用的分布式锁
+func (b *OptionsBuilder) FallbackLocker(locker Locker) *OptionsBuilder {
+	b.opts.fallbackLocker = locker
+	return b
+}
+
+// Concurrency 设置执行器级并发限制
+func (b *OptionsBuilder) Concurrency(cfg ConcurrencyConfig) *OptionsBuilder {
+	b.opts.concurrency = cfg
+	return b
+}
+
+// TaskConcurrency 为指定任务设置并发限制
+func (b *OptionsBuilder) TaskConcurrency(taskName string, cfg ConcurrencyConfig) *OptionsBuilder {
+	b.opts.taskConcurrency[taskName] = cfg
+	return b
+}
+
 // QuietMode 启用/禁用静默模式（不输出心跳/注册日志）
 func (b *OptionsBuilder) QuietMode(enabled bool) *OptionsBuilder {
 	b.opts.quietMode = enabled
@@ -297,13 +775,13 @@ func (b *OptionsBuilder) QuietMode(enabled bool) *OptionsBuilder {
 }
 
 // Middleware 添加中间件
-func (b *OptionsBuilder) Middleware(middleware Middleware) *OptionsBuilder {
+func (b *OptionsBuilder) Middleware(middleware TaskMiddleware) *OptionsBuilder {
 	b.opts.middlewares = append(b.opts.middlewares, middleware)
 	return b
 }
 
 // Middlewares 批量添加中间件
-func (b *OptionsBuilder) Middlewares(middlewares ...Middleware) *OptionsBuilder {
+func (b *OptionsBuilder) Middlewares(middlewares ...TaskMiddleware) *OptionsBuilder {
 	b.opts.middlewares = append(b.opts.middlewares, middlewares...)
 	return b
 }
@@ -347,27 +825,112 @@ func (o *executorOptions) WithLogPath(path string) *executorOptions {
 	return o
 }
 
+func (o *executorOptions) WithStateFile(path string) *executorOptions {
+	o.stateFile = path
+	return o
+}
+
 func (o *executorOptions) WithLogRetentionDays(days int) *executorOptions {
 	o.logRetentionDays = days
 	return o
 }
 
+func (o *executorOptions) WithLogSyncPolicy(policy LogSyncPolicy) *executorOptions {
+	o.logSyncPolicy = policy
+	return o
+}
+
+func (o *executorOptions) WithLogFlushInterval(interval time.Duration) *executorOptions {
+	o.logFlushInterval = interval
+	return o
+}
+
+func (o *executorOptions) WithLogBufferSize(size int) *executorOptions {
+	o.logBufferSize = size
+	return o
+}
+
+func (o *executorOptions) WithLogFormat(format LogFormat) *executorOptions {
+	o.logFormat = format
+	return o
+}
+
+func (o *executorOptions) WithLogLevel(level LogLevel) *executorOptions {
+	o.logLevel = level
+	return o
+}
+
+func (o *executorOptions) WithTaskLogLevel(taskName string, level LogLevel) *executorOptions {
+	o.taskLogLevels[taskName] = level
+	return o
+}
+
+func (o *executorOptions) WithLogStore(store LogStore) *executorOptions {
+	o.logStore = store
+	return o
+}
+
+func (o *executorOptions) WithRedactor(redactor *Redactor) *executorOptions {
+	o.redactor = redactor
+	return o
+}
+
 func (o *executorOptions) WithTrace(enabled bool) *executorOptions {
 	o.enableTrace = enabled
 	return o
 }
 
+func (o *executorOptions) WithTracePropagation(mode TracePropagationMode) *executorOptions {
+	o.tracePropagation = mode
+	return o
+}
+
+func (o *executorOptions) WithTaskTrace(taskName string, cfg TaskTraceConfig) *executorOptions {
+	o.taskTraces[taskName] = cfg
+	return o
+}
+
+func (o *executorOptions) WithTraceLogEvents(cfg TraceLogEventsConfig) *executorOptions {
+	o.traceLogEvents = cfg
+	return o
+}
+
+func (o *executorOptions) WithHistory(cfg HistoryConfig) *executorOptions {
+	o.history = cfg
+	return o
+}
+
+func (o *executorOptions) WithFallback(cfg FallbackConfig) *executorOptions {
+	o.fallback = cfg
+	return o
+}
+
+func (o *executorOptions) WithFallbackLocker(locker Locker) *executorOptions {
+	o.fallbackLocker = locker
+	return o
+}
+
+func (o *executorOptions) WithConcurrency(cfg ConcurrencyConfig) *executorOptions {
+	o.concurrency = cfg
+	return o
+}
+
+func (o *executorOptions) WithTaskConcurrency(taskName string, cfg ConcurrencyConfig) *executorOptions {
+	o.taskConcurrency[taskName] = cfg
+	return o
+}
+
 func (o *executorOptions) WithQuietMode(enabled bool) *executorOptions {
 	o.quietMode = enabled
 	return o
 }
 
-func (o *executorOptions) WithMiddleware(middleware Middleware) *executorOptions {
+func (o *executorOptions) WithMiddleware(middleware TaskMiddleware) *executorOptions {
 	o.middlewares = append(o.middlewares, middleware)
 	return o
 }
 
-func (o *executorOptions) WithMiddlewares(middlewares ...Middleware) *executorOptions {
+func (o *executorOptions) WithMiddlewares(middlewares ...TaskMiddleware) *executorOptions {
 	o.middlewares = append(o.middlewares, middlewares...)
 	return o
 }
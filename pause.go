@@ -0,0 +1,253 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-anyway/framework-log"
+	"go.uber.org/zap"
+)
+
+// PausedTask 暂停的任务
+type PausedTask struct {
+	Reason   string    `json:"reason"`
+	PausedAt time.Time `json:"paused_at"`
+}
+
+// executorState 本地状态文件内容
+type executorState struct {
+	PausedTasks map[string]PausedTask `json:"paused_tasks"`
+}
+
+// taskPauses 本地暂停的任务，path 不为空时每次变更都写入状态文件
+type taskPauses struct {
+	path string
+
+	mu     sync.RWMutex
+	paused map[string]PausedTask
+}
+
+// loadTaskPauses 从状态文件加载暂停的任务，文件不存在时返回空状态
+func loadTaskPauses(path string) (*taskPauses, error) {
+	p := &taskPauses{
+		path:   path,
+		paused: make(map[string]PausedTask),
+	}
+	if path == "" {
+		return p, nil
+	}
+
+	// #nosec G304 -- 文件路径来自配置
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return p, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var state executorState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return p, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	for name, paused := range state.PausedTasks {
+		p.paused[name] = paused
+	}
+	return p, nil
+}
+
+// pause 暂停任务，写入状态文件失败时恢复原状态
+func (p *taskPauses) pause(taskName, reason string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	previous, wasPaused := p.paused[taskName]
+	p.paused[taskName] = PausedTask{Reason: reason, PausedAt: time.Now()}
+	if err := p.saveLocked(); err != nil {
+		if wasPaused {
+			p.paused[taskName] = previous
+		} else {
+			delete(p.paused, taskName)
+		}
+		return err
+	}
+	return nil
+}
+
+// resume 恢复任务，返回任务之前是否处于暂停状态；写入状态文件失败时保持暂停
+func (p *taskPauses) resume(taskName string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	previous, ok := p.paused[taskName]
+	if !ok {
+		return false, nil
+	}
+	delete(p.paused, taskName)
+	if err := p.saveLocked(); err != nil {
+		p.paused[taskName] = previous
+		return false, err
+	}
+	return true, nil
+}
+
+// get 获取任务的暂停信息
+func (p *taskPauses) get(taskName string) (PausedTask, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	paused, ok := p.paused[taskName]
+	return paused, ok
+}
+
+// all 返回所有暂停的任务（副本）
+func (p *taskPauses) all() map[string]PausedTask {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	result := make(map[string]PausedTask, len(p.paused))
+	for name, paused := range p.paused {
+		result[name] = paused
+	}
+	return result
+}
+
+// saveLocked 写入状态文件（先写临时文件再重命名，避免写入中途崩溃损坏状态），调用方需持有 mu
+func (p *taskPauses) saveLocked() error {
+	if p.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(executorState{PausedTasks: p.paused}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	dir := filepath.Dir(p.path)
+	// #nosec G301 -- 状态目录需要可读权限
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(p.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p.path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
+
+// PauseTask 在本执行器上暂停任务，暂停期间的调度直接返回失败（携带暂停原因）
+// 可以暂停尚未注册的任务；配置了 StateFile 时暂停状态在重启后保留
+func (e *executorImpl) PauseTask(taskName, reason string) error {
+	if taskName == "" {
+		return fmt.Errorf("task name cannot be empty")
+	}
+	if err := e.pauses.pause(taskName, reason); err != nil {
+		return fmt.Errorf("failed to pause task %s: %w", taskName, err)
+	}
+	log.Warn("XXL-JOB task paused",
+		zap.String("task_name", taskName),
+		zap.String("reason", reason),
+	)
+	return nil
+}
+
+// ResumeTask 恢复暂停的任务，任务未暂停时不做任何操作
+func (e *executorImpl) ResumeTask(taskName string) error {
+	resumed, err := e.pauses.resume(taskName)
+	if err != nil {
+		return fmt.Errorf("failed to resume task %s: %w", taskName, err)
+	}
+	if resumed {
+		log.Info("XXL-JOB task resumed", zap.String("task_name", taskName))
+	}
+	return nil
+}
+
+// PausedTasks 返回本执行器上暂停的任务
+func (e *executorImpl) PausedTasks() map[string]PausedTask {
+	return e.pauses.all()
+}
+
+// pausedResult 任务暂停时返回给调度中心的结果，并写入任务日志
+func (e *executorImpl) pausedResult(ctx context.Context, taskName string, logID int64, paused PausedTask) string {
+	if logWriter := JobLogFromContext(ctx); logWriter != nil {
+		logWriter.Warn("Task skipped: paused", "reason", paused.Reason, "paused_at", paused.PausedAt.Format(time.RFC3339))
+	}
+	log.Warn("XXL-JOB task skipped: paused",
+		zap.String("task_name", taskName),
+		zap.Int64("log_id", logID),
+		zap.String("reason", paused.Reason),
+	)
+	return fmt.Sprintf("FAIL: %s", pausedMessage(paused))
+}
+
+// rejectPausedRequest 记录被拒绝的暂停任务调度请求；请求不会交给 SDK 执行，暂停原因直接写入任务日志，
+// 调度中心查看该日志 ID 时可以看到拒绝原因
+func (e *executorImpl) rejectPausedRequest(taskName string, logID int64, paused PausedTask) {
+	ctx := context.Background()
+	if logID > 0 {
+		logWriter, err := newLogWriter(e.logStore, logID, e.logWriterOptions(taskName))
+		if err != nil {
+			log.Warn("Failed to create log writer",
+				zap.Int64("log_id", logID),
+				zap.Error(err),
+			)
+		} else {
+			ctx = context.WithValue(ctx, logWriterKey, logWriter)
+			defer func() {
+				if closeErr := logWriter.Close(); closeErr != nil {
+					log.Warn("Failed to close log writer",
+						zap.Int64("log_id", logID),
+						zap.Error(closeErr),
+					)
+				}
+			}()
+		}
+	}
+	e.pausedResult(ctx, taskName, logID, paused)
+}
+
+// pausedMessage 返回暂停任务的失败消息
+func pausedMessage(paused PausedTask) string {
+	if paused.Reason == "" {
+		return "task paused on this executor"
+	}
+	return fmt.Sprintf("task paused on this executor: %s", paused.Reason)
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	xxl "github.com/xxl-job/xxl-job-executor-go"
+)
+
+func TestHandleRunPausedWritesJobLog(t *testing.T) {
+	e := newTestExecutor(t)
+	if err := e.RegTask("demo", func(context.Context, string) error {
+		t.Error("paused task executed")
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.PauseTask("demo", "maintenance"); err != nil {
+		t.Fatal(err)
+	}
+
+	ret := postAdmin(t, e.handleRun, runRequest("demo", 1, 7))
+	if ret.Code != int(xxl.FailureCode) || !strings.Contains(ret.Msg, "maintenance") {
+		t.Fatalf("run = %+v, want paused failure", ret)
+	}
+
+	// 调度中心查看日志时可以看到暂停原因
+	page, err := e.logStore.ReadPage(7, 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(page.Content, "Task skipped: paused") || !strings.Contains(page.Content, "maintenance") {
+		t.Errorf("job log = %q, want pause reason", page.Content)
+	}
+	if record, ok := historyRecord(e, "demo", 7); !ok || record.Status != ExecutionPaused {
+		t.Errorf("history = %+v, %v", record, ok)
+	}
+}
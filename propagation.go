@@ -0,0 +1,234 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceParamField 任务参数中保留的追踪上下文字段
+// 参数为 JSON 对象时，可以通过此字段传递 W3C 追踪上下文，例如：
+//
+//	{"_trace": {"traceparent": "00-...-...-01", "tracestate": "...", "baggage": "..."}, "other": 1}
+const TraceParamField = "_trace"
+
+// traceCarrierTTL 从请求头提取的追踪上下文的最长保留时间
+const traceCarrierTTL = time.Minute
+
+// TracePropagationMode 上游追踪上下文的关联方式
+type TracePropagationMode int
+
+const (
+	// TracePropagationParent 将上游 span 作为任务 span 的父 span（默认）
+	TracePropagationParent TracePropagationMode = iota
+	// TracePropagationLink 任务 span 作为新的根 span，并通过 Link 关联上游 span
+	TracePropagationLink
+	// TracePropagationNone 忽略上游追踪上下文
+	TracePropagationNone
+)
+
+// String 返回模式名称
+func (m TracePropagationMode) String() string {
+	switch m {
+	case TracePropagationParent:
+		return "parent"
+	case TracePropagationLink:
+		return "link"
+	case TracePropagationNone:
+		return "none"
+	default:
+		return fmt.Sprintf("TracePropagationMode(%d)", int(m))
+	}
+}
+
+// ParseTracePropagationMode 解析追踪上下文关联方式，空字符串返回 TracePropagationParent
+func ParseTracePropagationMode(name string) (TracePropagationMode, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "parent":
+		return TracePropagationParent, nil
+	case "link":
+		return TracePropagationLink, nil
+	case "none":
+		return TracePropagationNone, nil
+	default:
+		return TracePropagationParent, fmt.Errorf("unknown trace propagation mode: %s", name)
+	}
+}
+
+// tracePropagator W3C traceparent/tracestate 与 baggage 传播器
+var tracePropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// traceCarrierFromHeader 从 HTTP 请求头中提取追踪上下文字段
+func traceCarrierFromHeader(header http.Header) propagation.MapCarrier {
+	carrier := propagation.MapCarrier{}
+	for _, key := range tracePropagator.Fields() {
+		if value := header.Get(key); value != "" {
+			carrier.Set(key, value)
+		}
+	}
+	return carrier
+}
+
+// traceCarrierFromParam 从任务参数的保留字段中提取追踪上下文
+func traceCarrierFromParam(param string) propagation.MapCarrier {
+	trimmed := strings.TrimSpace(param)
+	if !strings.HasPrefix(trimmed, "{") || !strings.Contains(trimmed, TraceParamField) {
+		return nil
+	}
+
+	var payload struct {
+		Trace map[string]string `json:"_trace"`
+	}
+	if err := json.Unmarshal([]byte(trimmed), &payload); err != nil || len(payload.Trace) == 0 {
+		return nil
+	}
+
+	carrier := propagation.MapCarrier{}
+	for key, value := range payload.Trace {
+		carrier.Set(strings.ToLower(key), value)
+	}
+	return carrier
+}
+
+// extractTraceContext 从载体中提取上游追踪上下文
+// 返回携带 baggage（以及 Parent 模式下的远程 span）的 context，以及启动任务 span 时的额外选项
+func extractTraceContext(ctx context.Context, carrier propagation.MapCarrier, mode TracePropagationMode) (context.Context, []trace.SpanStartOption) {
+	if mode == TracePropagationNone || len(carrier) == 0 {
+		return ctx, nil
+	}
+
+	remoteCtx := tracePropagator.Extract(ctx, carrier)
+	remoteSpan := trace.SpanContextFromContext(remoteCtx)
+	if !remoteSpan.IsValid() {
+		// 没有有效的 traceparent 时，仅保留 baggage
+		return remoteCtx, nil
+	}
+
+	if mode == TracePropagationLink {
+		// 保留 baggage，任务 span 作为新的根 span 并链接到远程 span
+		return remoteCtx, []trace.SpanStartOption{
+			trace.WithNewRoot(),
+			trace.WithLinks(trace.Link{SpanContext: remoteSpan}),
+		}
+	}
+	return remoteCtx, nil
+}
+
+// InjectTraceContext 将当前追踪上下文写入任务参数的保留字段
+// 用于在任务中触发子任务时向下游传递追踪上下文。param 必须为空或 JSON 对象
+func InjectTraceContext(ctx context.Context, param string) (string, error) {
+	carrier := propagation.MapCarrier{}
+	tracePropagator.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return param, nil
+	}
+
+	payload := make(map[string]json.RawMessage)
+	if trimmed := strings.TrimSpace(param); trimmed != "" {
+		if err := json.Unmarshal([]byte(trimmed), &payload); err != nil {
+			return param, fmt.Errorf("param must be a JSON object to carry trace context: %w", err)
+		}
+	}
+
+	traceField, err := json.Marshal(map[string]string(carrier))
+	if err != nil {
+		return param, fmt.Errorf("failed to encode trace context: %w", err)
+	}
+	payload[TraceParamField] = traceField
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return param, fmt.Errorf("failed to encode param: %w", err)
+	}
+	return string(data), nil
+}
+
+// InjectTraceHeaders 将当前追踪上下文写入 HTTP 请求头
+// 用于通过调度中心 API 触发下游任务时传递追踪上下文
+func InjectTraceHeaders(ctx context.Context, header http.Header) {
+	tracePropagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// traceCarrierStore 暂存从 /run 请求头中提取的追踪上下文，按日志 ID 索引
+// 任务开始执行时取出；未被取出的条目（如调度被拒绝）超过 TTL 后清理
+type traceCarrierStore struct {
+	mu      sync.Mutex
+	entries map[int64]traceCarrierEntry
+}
+
+// traceCarrierEntry 暂存的追踪上下文
+type traceCarrierEntry struct {
+	carrier  propagation.MapCarrier
+	storedAt time.Time
+}
+
+// newTraceCarrierStore 创建追踪上下文暂存
+func newTraceCarrierStore() *traceCarrierStore {
+	return &traceCarrierStore{
+		entries: make(map[int64]traceCarrierEntry),
+	}
+}
+
+// Put 暂存追踪上下文
+func (s *traceCarrierStore) Put(logID int64, carrier propagation.MapCarrier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, entry := range s.entries {
+		if now.Sub(entry.storedAt) > traceCarrierTTL {
+			delete(s.entries, id)
+		}
+	}
+	s.entries[logID] = traceCarrierEntry{carrier: carrier, storedAt: now}
+}
+
+// Take 取出并删除追踪上下文
+func (s *traceCarrierStore) Take(logID int64) propagation.MapCarrier {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[logID]
+	if !ok {
+		return nil
+	}
+	delete(s.entries, logID)
+	return entry.carrier
+}
+
+// mergeTraceCarriers 合并追踪上下文，后面的载体优先
+func mergeTraceCarriers(carriers ...propagation.MapCarrier) propagation.MapCarrier {
+	merged := propagation.MapCarrier{}
+	for _, carrier := range carriers {
+		for key, value := range carrier {
+			merged[key] = value
+		}
+	}
+	return merged
+}
@@ -0,0 +1,264 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// redactedValue 脱敏后的替换值
+	redactedValue = "***"
+	// defaultMaxAttributeLength 追踪属性默认最大长度
+	defaultMaxAttributeLength = 256
+)
+
+// defaultRedactKeys 默认的敏感字段名（匹配时忽略大小写、下划线和连字符，按包含关系匹配）
+var defaultRedactKeys = []string{
+	"password", "passwd", "pwd", "secret", "token", "apikey",
+	"accesskey", "privatekey", "authorization", "credential",
+}
+
+// RedactionConfig 任务参数脱敏配置
+// 零值即启用，使用内置的敏感字段名列表；可通过 DisabledTasks 为单个任务关闭
+type RedactionConfig struct {
+	Disabled           bool     `yaml:"disabled" env:"XXL_JOB_REDACTION_DISABLED" default:"false"`
+	Keys               []string `yaml:"keys" env:"XXL_JOB_REDACTION_KEYS"`                     // 追加的敏感字段名
+	JSONPaths          []string `yaml:"json_paths" env:"XXL_JOB_REDACTION_JSON_PATHS"`         // 例如 customer.id、items.*.card
+	Patterns           []string `yaml:"patterns" env:"XXL_JOB_REDACTION_PATTERNS"`             // 正则表达式，匹配内容会被替换
+	DisabledTasks      []string `yaml:"disabled_tasks" env:"XXL_JOB_REDACTION_DISABLED_TASKS"` // 不做脱敏的任务
+	MaxAttributeLength int      `yaml:"max_attribute_length" env:"XXL_JOB_REDACTION_MAX_ATTRIBUTE_LENGTH" default:"256"`
+}
+
+// DefaultRedactionConfig 返回默认脱敏配置
+func DefaultRedactionConfig() RedactionConfig {
+	return RedactionConfig{
+		MaxAttributeLength: defaultMaxAttributeLength,
+	}
+}
+
+// Redactor 任务参数脱敏器
+// 在参数写入文件日志、zap 日志和追踪属性之前进行脱敏，任务处理器收到的仍是原始参数。
+// 对 JSON 参数按字段名和 JSON 路径脱敏；对所有参数应用正则规则，
+// 并对 key=value、key: value 形式中的敏感字段脱敏
+type Redactor struct {
+	enabled       bool
+	keys          []string
+	paths         [][]string
+	patterns      []*regexp.Regexp
+	keyValue      *regexp.Regexp
+	disabledTasks map[string]struct{}
+	maxAttrLen    int
+}
+
+// NewRedactor 根据配置创建脱敏器
+func NewRedactor(cfg RedactionConfig) (*Redactor, error) {
+	r := &Redactor{
+		enabled:       !cfg.Disabled,
+		disabledTasks: make(map[string]struct{}, len(cfg.DisabledTasks)),
+		maxAttrLen:    cfg.MaxAttributeLength,
+	}
+	if r.maxAttrLen <= 0 {
+		r.maxAttrLen = defaultMaxAttributeLength
+	}
+
+	for _, key := range append(append([]string(nil), defaultRedactKeys...), cfg.Keys...) {
+		if normalized := normalizeRedactKey(key); normalized != "" {
+			r.keys = append(r.keys, normalized)
+		}
+	}
+
+	for _, path := range cfg.JSONPaths {
+		path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+		if path == "" {
+			return nil, fmt.Errorf("invalid redaction json path: %q", path)
+		}
+		r.paths = append(r.paths, strings.Split(path, "."))
+	}
+
+	for _, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	// 匹配 key=value / key: value 形式，用于非 JSON 参数（如查询字符串）
+	r.keyValue = regexp.MustCompile(`([A-Za-z0-9_\-.]+)(\s*[=:]\s*)([^&\s,;]+)`)
+
+	for _, task := range cfg.DisabledTasks {
+		r.disabledTasks[task] = struct{}{}
+	}
+	return r, nil
+}
+
+// defaultRedactor 默认脱敏器
+func defaultRedactor() *Redactor {
+	r, _ := NewRedactor(DefaultRedactionConfig())
+	return r
+}
+
+// Enabled 判断指定任务是否需要脱敏
+func (r *Redactor) Enabled(taskName string) bool {
+	if r == nil || !r.enabled {
+		return false
+	}
+	_, disabled := r.disabledTasks[taskName]
+	return !disabled
+}
+
+// Redact 对任务参数脱敏
+func (r *Redactor) Redact(taskName, param string) string {
+	if !r.Enabled(taskName) || param == "" {
+		return param
+	}
+
+	if redacted, ok := r.redactJSON(param); ok {
+		param = redacted
+	} else {
+		param = r.keyValue.ReplaceAllStringFunc(param, func(match string) string {
+			sub := r.keyValue.FindStringSubmatch(match)
+			if r.isSensitiveKey(sub[1]) {
+				return sub[1] + sub[2] + redactedValue
+			}
+			return match
+		})
+	}
+
+	for _, re := range r.patterns {
+		param = re.ReplaceAllString(param, redactedValue)
+	}
+	return param
+}
+
+// TruncateAttribute 截断追踪属性值，避免超长属性
+func (r *Redactor) TruncateAttribute(value string) string {
+	maxLen := defaultMaxAttributeLength
+	if r != nil {
+		maxLen = r.maxAttrLen
+	}
+	if len(value) <= maxLen {
+		return value
+	}
+	// 按 UTF-8 字符边界截断
+	cut := maxLen
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut] + "...(truncated)"
+}
+
+// redactJSON 解析 JSON 参数并脱敏，不是 JSON 时返回 false
+func (r *Redactor) redactJSON(param string) (string, bool) {
+	trimmed := strings.TrimSpace(param)
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return "", false
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", false
+	}
+
+	value = r.redactValue(value, nil)
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", false
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), true
+}
+
+// redactValue 递归脱敏 JSON 值
+func (r *Redactor) redactValue(value interface{}, path []string) interface{} {
+	if r.matchPath(path) {
+		return redactedValue
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if r.isSensitiveKey(key) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = r.redactValue(child, append(path, key))
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = r.redactValue(child, append(path, fmt.Sprint(i)))
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// matchPath 判断当前路径是否匹配任一 JSON 路径规则
+func (r *Redactor) matchPath(path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	for _, rule := range r.paths {
+		if len(rule) != len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range rule {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// isSensitiveKey 判断字段名是否在敏感字段列表中
+func (r *Redactor) isSensitiveKey(key string) bool {
+	normalized := normalizeRedactKey(key)
+	if normalized == "" {
+		return false
+	}
+	for _, k := range r.keys {
+		if strings.Contains(normalized, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeRedactKey 规范化字段名：小写并去除下划线、连字符
+func normalizeRedactKey(key string) string {
+	key = strings.ToLower(key)
+	key = strings.ReplaceAll(key, "_", "")
+	return strings.ReplaceAll(key, "-", "")
+}
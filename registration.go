@@ -0,0 +1,96 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"fmt"
+	stdlog "log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// sdkRegistrySuccessMsg SDK 注册成功日志前缀
+	sdkRegistrySuccessMsg = "执行器注册成功"
+	// sdkRegistryFailureMsg SDK 注册失败日志前缀
+	sdkRegistryFailureMsg = "执行器注册失败"
+)
+
+// registrationMonitor 记录执行器向调度中心注册的结果
+// SDK 没有暴露注册状态，通过 sdkLogger 观察注册日志获得
+type registrationMonitor struct {
+	mu           sync.RWMutex
+	lastSuccess  time.Time
+	failingSince time.Time // 连续注册失败的开始时间，注册成功后清零
+}
+
+// observe 根据 SDK 日志更新注册状态
+func (m *registrationMonitor) observe(msg string) {
+	switch {
+	case strings.HasPrefix(msg, sdkRegistrySuccessMsg):
+		m.mu.Lock()
+		m.lastSuccess = time.Now()
+		m.failingSince = time.Time{}
+		m.mu.Unlock()
+	case strings.HasPrefix(msg, sdkRegistryFailureMsg):
+		m.mu.Lock()
+		if m.failingSince.IsZero() {
+			m.failingSince = time.Now()
+		}
+		m.mu.Unlock()
+	}
+}
+
+// failingFor 返回连续注册失败的时长，当前未失败时返回 0
+func (m *registrationMonitor) failingFor() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.failingSince.IsZero() {
+		return 0
+	}
+	return time.Since(m.failingSince)
+}
+
+// lastRegistered 返回最近一次注册成功的时间
+func (m *registrationMonitor) lastRegistered() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastSuccess
+}
+
+// sdkLogger SDK 日志适配器：输出方式与 SDK 默认日志一致（由日志拦截器统一处理），同时观察注册结果
+type sdkLogger struct {
+	registration *registrationMonitor
+}
+
+// Info 输出 SDK 信息日志，静默模式下不输出心跳/注册成功日志
+func (l *sdkLogger) Info(format string, a ...interface{}) {
+	msg := fmt.Sprintf(format, a...)
+	l.registration.observe(msg)
+	if sdkQuietMode.Load() && shouldFilterHeartbeatLog(msg) {
+		return
+	}
+	fmt.Println(msg)
+}
+
+// Error 输出 SDK 错误日志
+func (l *sdkLogger) Error(format string, a ...interface{}) {
+	msg := fmt.Sprintf(format, a...)
+	l.registration.observe(msg)
+	stdlog.Println(msg)
+}
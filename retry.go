@@ -0,0 +1,262 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-anyway/framework-metrics"
+	pkgtrace "github.com/go-anyway/framework-trace"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// defaultRetryMultiplier 默认退避倍数
+	defaultRetryMultiplier = 2.0
+	// defaultDecorrelatedBackoff 去相关抖动未设置初始退避时使用的基准等待时间
+	defaultDecorrelatedBackoff = 100 * time.Millisecond
+)
+
+// JitterMode 退避抖动方式
+type JitterMode int
+
+const (
+	// JitterNone 不抖动（默认）
+	JitterNone JitterMode = iota
+	// JitterFull 全抖动：在 [0, 退避时间] 内随机
+	JitterFull
+	// JitterDecorrelated 去相关抖动：在 [初始退避, 上次退避*3] 内随机，初始退避为 0 时以 100ms 为基准
+	JitterDecorrelated
+)
+
+// RetryPolicy 重试策略
+type RetryPolicy struct {
+	MaxRetries     int           // 最大重试次数（不含首次执行）
+	InitialBackoff time.Duration // 首次重试前的等待时间
+	MaxBackoff     time.Duration // 单次等待时间上限，0 表示不限制
+	Multiplier     float64       // 退避倍数，默认 2
+	Jitter         JitterMode    // 抖动方式
+	MaxElapsedTime time.Duration // 从首次执行开始的总时长上限，超出后不再重试，0 表示不限制
+	// Retryable 判断错误是否可重试，Permanent 错误和 context 取消总是不重试；
+	// 为空时熔断（ErrCircuitOpen）、锁被占用（ErrLockHeld）、重复执行（ErrDuplicateInProgress）
+	// 和并发上限（ErrConcurrencyLimit）也不重试，这些拒绝在短时间内重试不会成功
+	Retryable func(err error) bool
+}
+
+// permanentError 不可重试的错误
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent 将错误标记为不可重试，RetryPolicyMiddleware 遇到时直接返回
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent 判断错误是否被标记为不可重试
+func IsPermanent(err error) bool {
+	var pe *permanentError
+	return errors.As(err, &pe)
+}
+
+// retryable 判断错误是否可重试
+func (p RetryPolicy) retryable(err error) bool {
+	if IsPermanent(err) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return !errors.Is(err, ErrCircuitOpen) &&
+		!errors.Is(err, ErrLockHeld) &&
+		!errors.Is(err, ErrDuplicateInProgress) &&
+		!errors.Is(err, ErrConcurrencyLimit)
+}
+
+// backoff 计算第 retry 次重试（从 1 开始）前的等待时间，prev 为上一次的等待时间
+func (p RetryPolicy) backoff(retry int, prev time.Duration) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = defaultRetryMultiplier
+	}
+
+	var wait time.Duration
+	switch p.Jitter {
+	case JitterDecorrelated:
+		base := p.InitialBackoff
+		if base <= 0 {
+			base = defaultDecorrelatedBackoff
+		}
+		upper := time.Duration(float64(max(prev, base)) * 3)
+		wait = base + randDuration(upper-base)
+	default:
+		wait = p.InitialBackoff
+		for i := 1; i < retry; i++ {
+			next := float64(wait) * multiplier
+			if next >= math.MaxInt64 {
+				wait = math.MaxInt64
+				break
+			}
+			wait = time.Duration(next)
+			if p.MaxBackoff > 0 && wait >= p.MaxBackoff {
+				break
+			}
+		}
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if p.Jitter == JitterFull {
+		wait = randDuration(wait)
+	}
+	return wait
+}
+
+// randDuration 返回 [0, d] 内的随机时长
+func randDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(d) + 1))
+}
+
+// RetryPolicyMiddleware 按重试策略重试的中间件
+// 每次失败写入任务日志；启用追踪时每次尝试记录为子 span，总尝试次数记录在任务 span 和指标中
+func RetryPolicyMiddleware(policy RetryPolicy) Middleware {
+	return func(next TaskHandler) TaskHandler {
+		return func(ctx context.Context, param string) error {
+			info, _ := RunInfoFromContext(ctx)
+			logWriter := JobLogFromContext(ctx)
+			start := time.Now()
+
+			attempts := 0
+			defer func() {
+				trace.SpanFromContext(ctx).SetAttributes(attribute.Int("xxljob.retry.attempts", attempts))
+				if metrics.IsEnabled() {
+					xxlJobAttempts.WithLabelValues(info.TaskName).Observe(float64(attempts))
+				}
+			}()
+
+			var wait time.Duration
+			for {
+				attempts++
+				err := runAttempt(ctx, param, next, attempts, wait)
+				if err == nil {
+					return nil
+				}
+				if ctx.Err() != nil {
+					return retryStopped(ctx, err)
+				}
+
+				if !policy.retryable(err) {
+					if logWriter != nil && policy.MaxRetries > 0 {
+						logWriter.Warn("Attempt failed with non-retryable error", "attempt", attempts, "error", err)
+					}
+					return err
+				}
+				if attempts > policy.MaxRetries {
+					if logWriter != nil && policy.MaxRetries > 0 {
+						logWriter.Error("Retries exhausted", "attempts", attempts, "error", err)
+					}
+					return err
+				}
+
+				wait = policy.backoff(attempts, wait)
+				if policy.MaxElapsedTime > 0 && time.Since(start)+wait > policy.MaxElapsedTime {
+					if logWriter != nil {
+						logWriter.Error("Retry stopped: max elapsed time exceeded",
+							"attempts", attempts,
+							"max_elapsed", policy.MaxElapsedTime,
+							"error", err,
+						)
+					}
+					return err
+				}
+
+				if logWriter != nil {
+					logWriter.Warn(fmt.Sprintf("Attempt %d/%d failed, retrying", attempts, policy.MaxRetries+1),
+						"backoff", wait,
+						"error", err,
+					)
+				}
+				if metrics.IsEnabled() {
+					xxlJobRetriesTotal.WithLabelValues(info.TaskName).Inc()
+				}
+
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return retryStopped(ctx, err)
+				case <-timer.C:
+				}
+			}
+		}
+	}
+}
+
+// retryStopped 任务上下文结束时停止重试，返回的错误同时包含 context 错误和最后一次尝试的错误
+func retryStopped(ctx context.Context, err error) error {
+	if errors.Is(err, ctx.Err()) {
+		return err
+	}
+	return fmt.Errorf("%w (last attempt: %w)", ctx.Err(), err)
+}
+
+// runAttempt 执行一次尝试
+// 上下文中存在有效的任务 span 时，为本次尝试创建子 span
+func runAttempt(ctx context.Context, param string, next TaskHandler, attempt int, backoff time.Duration) error {
+	if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		return next(ctx, param)
+	}
+
+	ctx, span := pkgtrace.StartSpan(ctx, "xxljob.task.attempt",
+		trace.WithAttributes(
+			attribute.Int("xxljob.retry.attempt", attempt),
+			attribute.Int64("xxljob.retry.backoff_ms", backoff.Milliseconds()),
+		),
+	)
+	defer span.End()
+
+	err := next(ctx, param)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	return err
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		want   []time.Duration // 第 1、2、3... 次重试前的等待时间
+	}{
+		{
+			name:   "default multiplier",
+			policy: RetryPolicy{InitialBackoff: time.Second},
+			want:   []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		{
+			name:   "custom multiplier",
+			policy: RetryPolicy{InitialBackoff: time.Second, Multiplier: 1.5},
+			want:   []time.Duration{time.Second, 1500 * time.Millisecond, 2250 * time.Millisecond},
+		},
+		{
+			name:   "capped by max backoff",
+			policy: RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 3 * time.Second},
+			want:   []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second},
+		},
+		{
+			name:   "zero initial backoff",
+			policy: RetryPolicy{},
+			want:   []time.Duration{0, 0, 0},
+		},
+		{
+			name:   "overflow saturates",
+			policy: RetryPolicy{InitialBackoff: time.Hour, Multiplier: 1e6},
+			want:   []time.Duration{time.Hour, 1e6 * time.Hour, math.MaxInt64, math.MaxInt64},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prev time.Duration
+			for i, want := range tt.want {
+				got := tt.policy.backoff(i+1, prev)
+				if got != want {
+					t.Errorf("retry %d: backoff = %s, want %s", i+1, got, want)
+				}
+				prev = got
+			}
+		})
+	}
+}
+
+func TestRetryBackoffJitter(t *testing.T) {
+	t.Run("full", func(t *testing.T) {
+		policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 3 * time.Second, Jitter: JitterFull}
+		for i := 0; i < 100; i++ {
+			if got := policy.backoff(5, 0); got < 0 || got > 3*time.Second {
+				t.Fatalf("backoff = %s, want within [0, 3s]", got)
+			}
+		}
+	})
+
+	t.Run("decorrelated", func(t *testing.T) {
+		for _, initial := range []time.Duration{0, 50 * time.Millisecond} {
+			policy := RetryPolicy{InitialBackoff: initial, MaxBackoff: 10 * time.Second, Jitter: JitterDecorrelated}
+			base := initial
+			if base == 0 {
+				base = defaultDecorrelatedBackoff
+			}
+			var prev time.Duration
+			grew := false
+			for i := 1; i <= 50; i++ {
+				got := policy.backoff(i, prev)
+				upper := min(3*max(prev, base), policy.MaxBackoff)
+				if got < base || got > upper {
+					t.Fatalf("initial %s retry %d: backoff = %s, want within [%s, %s]", initial, i, got, base, upper)
+				}
+				grew = grew || got > base
+				prev = got
+			}
+			if !grew {
+				t.Errorf("initial %s: backoff never grew beyond %s", initial, base)
+			}
+		}
+	})
+}
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	handlerErr := errors.New("temporary")
+	tests := []struct {
+		name      string
+		retryable func(error) bool
+		err       error
+		want      bool
+	}{
+		{"plain error", nil, handlerErr, true},
+		{"permanent", nil, Permanent(handlerErr), false},
+		{"canceled", nil, fmt.Errorf("wrapped: %w", context.Canceled), false},
+		{"circuit open", nil, fmt.Errorf("%w: demo", ErrCircuitOpen), false},
+		{"lock held", nil, fmt.Errorf("%w: demo", ErrLockHeld), false},
+		{"duplicate", nil, fmt.Errorf("%w: log_id=1", ErrDuplicateInProgress), false},
+		{"concurrency limit", nil, ErrConcurrencyLimit, false},
+		{"custom allows lock held", func(error) bool { return true }, ErrLockHeld, true},
+		{"custom cannot retry permanent", func(error) bool { return true }, Permanent(handlerErr), false},
+		{"custom rejects", func(error) bool { return false }, handlerErr, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := RetryPolicy{Retryable: tt.retryable}
+			if got := policy.retryable(tt.err); got != tt.want {
+				t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyMiddlewareContextDone(t *testing.T) {
+	handlerErr := errors.New("handler failed")
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(lockTestContext("demo"))
+		attempts := 0
+		handler := RetryPolicyMiddleware(RetryPolicy{MaxRetries: 3, InitialBackoff: time.Hour})(
+			func(context.Context, string) error {
+				attempts++
+				time.AfterFunc(10*time.Millisecond, cancel)
+				return handlerErr
+			})
+
+		err := handler(ctx, "")
+		if !errors.Is(err, context.Canceled) || !errors.Is(err, handlerErr) {
+			t.Errorf("error = %v, want context.Canceled wrapping the handler error", err)
+		}
+		if attempts != 1 {
+			t.Errorf("attempts = %d, want 1", attempts)
+		}
+	})
+
+	t.Run("deadline during attempt", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(lockTestContext("demo"), 10*time.Millisecond)
+		defer cancel()
+		handler := RetryPolicyMiddleware(RetryPolicy{MaxRetries: 3})(
+			func(ctx context.Context, _ string) error {
+				<-ctx.Done()
+				return handlerErr
+			})
+
+		err := handler(ctx, "")
+		if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, handlerErr) {
+			t.Errorf("error = %v, want context.DeadlineExceeded wrapping the handler error", err)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import "context"
+
+const runInfoKey = contextKey("xxljob_run_info")
+
+// RunInfo 当前任务执行的调度信息
+type RunInfo struct {
+	TaskName   string // 任务名称（JobHandler）
+	LogID      int64  // 本次调度日志 ID
+	JobID      int64  // 调度中心任务 ID
+	ShardIndex int64  // 分片序号
+	ShardTotal int64  // 分片总数
+}
+
+// RunInfoFromContext 获取当前任务执行的调度信息，不在任务上下文中时返回 false
+func RunInfoFromContext(ctx context.Context) (RunInfo, bool) {
+	if ctx == nil {
+		return RunInfo{}, false
+	}
+	info, ok := ctx.Value(runInfoKey).(RunInfo)
+	return info, ok
+}
+
+// contextWithRunInfo 将调度信息注入到 context
+func contextWithRunInfo(ctx context.Context, run taskRun) context.Context {
+	return context.WithValue(ctx, runInfoKey, RunInfo{
+		TaskName:   run.taskName,
+		LogID:      run.logID,
+		JobID:      run.jobID,
+		ShardIndex: run.shardIndex,
+		ShardTotal: run.shardTotal,
+	})
+}
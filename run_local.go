@@ -0,0 +1,157 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	xxl "github.com/xxl-job/xxl-job-executor-go"
+)
+
+// runLocalOptions 本地执行选项
+type runLocalOptions struct {
+	logID      int64
+	jobID      int64
+	shardIndex int64
+	shardTotal int64
+	output     io.Writer
+}
+
+// RunLocalOption 本地执行选项函数
+type RunLocalOption func(*runLocalOptions)
+
+// WithLocalLogID 设置本地执行使用的日志 ID（默认使用当前时间戳生成）
+func WithLocalLogID(logID int64) RunLocalOption {
+	return func(o *runLocalOptions) {
+		if logID > 0 {
+			o.logID = logID
+		}
+	}
+}
+
+// WithLocalJobID 设置本地执行使用的调度中心任务 ID
+func WithLocalJobID(jobID int64) RunLocalOption {
+	return func(o *runLocalOptions) {
+		o.jobID = jobID
+	}
+}
+
+// WithLocalShard 设置分片参数
+func WithLocalShard(index, total int64) RunLocalOption {
+	return func(o *runLocalOptions) {
+		o.shardIndex = index
+		o.shardTotal = total
+	}
+}
+
+// WithLocalOutput 设置任务日志的输出位置（默认标准输出）
+func WithLocalOutput(w io.Writer) RunLocalOption {
+	return func(o *runLocalOptions) {
+		if w != nil {
+			o.output = w
+		}
+	}
+}
+
+// RunLocal 在本地执行已注册的任务，不经过调度中心
+// 与调度中心触发走相同的执行路径（中间件链、超时、并发限制、追踪、执行历史），任务日志写入 output。
+// 返回结果与调度中心收到的一致，结果不是 SUCCESS 时同时返回 error，便于在命令行中设置退出码：
+//
+//	if *runTask != "" {
+//		result, err := xxljob.RunLocal(ctx, exec, *runTask, *runParam)
+//		fmt.Println(result)
+//		if err != nil {
+//			os.Exit(1)
+//		}
+//		return
+//	}
+func RunLocal(ctx context.Context, exec Executor, taskName, param string, opts ...RunLocalOption) (string, error) {
+	e, ok := exec.(*executorImpl)
+	if !ok {
+		return "", fmt.Errorf("unsupported executor type %T", exec)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	o := &runLocalOptions{
+		logID:  time.Now().UnixMilli(),
+		output: os.Stdout,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	result := e.runTaskRecover(ctx, taskName, &xxl.RunReq{
+		JobID:           o.jobID,
+		ExecutorHandler: taskName,
+		ExecutorParams:  param,
+		LogID:           o.logID,
+		BroadcastIndex:  o.shardIndex,
+		BroadcastTotal:  o.shardTotal,
+	}, &writerLogStore{w: o.output})
+	if !strings.HasPrefix(result, "SUCCESS") {
+		return result, errors.New(result)
+	}
+	return result, nil
+}
+
+// writerLogStore 将任务日志写入 io.Writer 的日志存储，仅用于本地执行，不支持读取
+type writerLogStore struct {
+	w io.Writer
+}
+
+// OpenWriter 返回写入 w 的日志写入端
+func (s *writerLogStore) OpenWriter(int64) (LogSink, error) {
+	return writerSink{s.w}, nil
+}
+
+// ReadPage 不支持读取
+func (s *writerLogStore) ReadPage(logID int64, _ int, _ int) (*LogPage, error) {
+	return nil, fmt.Errorf("%w: %d", ErrLogNotFound, logID)
+}
+
+// Delete 无需删除
+func (s *writerLogStore) Delete(int64) error {
+	return nil
+}
+
+// List 没有可列出的日志
+func (s *writerLogStore) List() ([]LogInfo, error) {
+	return nil, nil
+}
+
+// writerSink 写入 io.Writer 的日志写入端，关闭时不关闭底层 Writer
+type writerSink struct {
+	io.Writer
+}
+
+// Sync 无需同步
+func (writerSink) Sync() error {
+	return nil
+}
+
+// Close 不关闭底层 Writer
+func (writerSink) Close() error {
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunLocal(t *testing.T) {
+	e := newTestExecutor(t)
+	if err := e.RegTask("ok", func(ctx context.Context, param string) error {
+		JobLogFromContext(ctx).Info("hello " + param)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	result, err := RunLocal(context.Background(), e, "ok", "world", WithLocalOutput(&out), WithLocalLogID(1))
+	if err != nil || !strings.HasPrefix(result, "SUCCESS") {
+		t.Fatalf("RunLocal = %q, %v; want success", result, err)
+	}
+	if !strings.Contains(out.String(), "hello world") {
+		t.Errorf("output = %q, want job log", out.String())
+	}
+
+	if result, err := RunLocal(context.Background(), e, "missing", ""); err == nil {
+		t.Errorf("RunLocal unknown task = %q, want error", result)
+	}
+}
+
+// 本地执行和兜底调度不经过 SDK，任务 panic 时返回失败结果并记录执行历史，不能导致进程退出
+func TestRunTaskRecoverPanic(t *testing.T) {
+	e := newTestExecutor(t)
+	if err := e.RegTask("boom", func(context.Context, string) error {
+		panic("boom")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := RunLocal(context.Background(), e, "boom", "", WithLocalOutput(&bytes.Buffer{}), WithLocalLogID(1))
+	if err == nil || result != "FAIL: task panic: boom" {
+		t.Fatalf("RunLocal = %q, %v; want panic failure", result, err)
+	}
+	if record, ok := historyRecord(e, "boom", 1); !ok || record.Status != ExecutionFailed || record.Error != "task panic: boom" {
+		t.Errorf("history = %+v, %v", record, ok)
+	}
+
+	scheduler := &fallbackScheduler{e: e}
+	scheduler.fire(FallbackTaskConfig{Task: "boom"}, time.Now())
+	records := e.ExecutionHistory("boom", HistoryFilter{})
+	if len(records) != 2 || records[0].Status != ExecutionFailed || records[1].Status != ExecutionFailed {
+		t.Errorf("history after fallback trigger = %+v", records)
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/go-anyway/framework-log"
+
+	xxl "github.com/xxl-job/xxl-job-executor-go"
+	"go.uber.org/zap"
+)
+
+const (
+	// serverWriteTimeout 执行器 HTTP 服务写超时（与 SDK 保持一致）
+	serverWriteTimeout = 3 * time.Second
+	// serverShutdownTimeout 执行器 HTTP 服务关闭超时
+	serverShutdownTimeout = 5 * time.Second
+	// maxRunRequestSize /run 请求体最大大小（10MB）
+	maxRunRequestSize = 10 * 1024 * 1024
+)
+
+// newServeMux 创建执行器 HTTP 路由
+// 调度中心协议接口仍由 SDK 处理，这里只做请求预处理
+func (e *executorImpl) newServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/run", e.handleRun)
+	mux.HandleFunc("/kill", e.executor.KillTask)
+	mux.HandleFunc("/log", e.executor.TaskLog)
+	mux.HandleFunc("/beat", e.executor.Beat)
+	mux.HandleFunc("/idleBeat", e.handleIdleBeat)
+	mux.HandleFunc("/health", e.requireToken(e.handleHealth))
+	mux.HandleFunc("/history", e.requireToken(e.handleHistory))
+	return mux
+}
+
+// requireToken 配置了 AccessToken 时，校验请求头中的 XXL-JOB-ACCESS-TOKEN
+// 用于执行器自身提供的管理接口（调度中心协议接口由 SDK 处理）
+func (e *executorImpl) requireToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if e.options().accessToken != "" &&
+			subtle.ConstantTimeCompare([]byte(r.Header.Get("XXL-JOB-ACCESS-TOKEN")), []byte(e.options().accessToken)) != 1 {
+			http.Error(w, "invalid access token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleHealth 返回执行器健康状态（JSON）
+func (e *executorImpl) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	status := e.GetHealthStatus()
+	resp := struct {
+		Running         bool                  `json:"running"`
+		TaskCount       int                   `json:"task_count"`
+		StartedAt       time.Time             `json:"started_at"`
+		LastError       string                `json:"last_error,omitempty"`
+		RunningTasks    map[string]int        `json:"running_tasks"`
+		CircuitBreakers []CircuitBreakerState `json:"circuit_breakers"`
+		PausedTasks     map[string]PausedTask `json:"paused_tasks"`
+		RegisteredAt    time.Time             `json:"registered_at"`
+		FallbackActive  bool                  `json:"fallback_active"`
+	}{
+		Running:         status.Running,
+		TaskCount:       status.TaskCount,
+		StartedAt:       status.StartedAt,
+		RunningTasks:    status.RunningTasks,
+		CircuitBreakers: status.CircuitBreakers,
+		PausedTasks:     status.PausedTasks,
+		RegisteredAt:    status.RegisteredAt,
+		FallbackActive:  status.FallbackActive,
+	}
+	if status.LastError != nil {
+		resp.LastError = status.LastError.Error()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleHistory 返回执行历史（JSON）
+// 查询参数：task、status、log_id、job_id、since、until（RFC3339）、limit（默认 100）
+func (e *executorImpl) handleHistory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := HistoryFilter{
+		Status: ExecutionStatus(query.Get("status")),
+		Limit:  defaultHistorySize,
+	}
+
+	var err error
+	parseInt := func(name string, dst *int64) {
+		if v := query.Get(name); v != "" && err == nil {
+			if *dst, err = strconv.ParseInt(v, 10, 64); err != nil {
+				err = fmt.Errorf("invalid %s: %s", name, v)
+			}
+		}
+	}
+	parseTime := func(name string, dst *time.Time) {
+		if v := query.Get(name); v != "" && err == nil {
+			if *dst, err = time.Parse(time.RFC3339, v); err != nil {
+				err = fmt.Errorf("invalid %s: %s", name, v)
+			}
+		}
+	}
+	parseInt("log_id", &filter.LogID)
+	parseInt("job_id", &filter.JobID)
+	parseTime("since", &filter.Since)
+	parseTime("until", &filter.Until)
+	if v := query.Get("limit"); v != "" && err == nil {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			err = fmt.Errorf("invalid limit: %s", v)
+		}
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	records := e.ExecutionHistory(query.Get("task"), filter)
+	if records == nil {
+		records = []ExecutionRecord{}
+	}
+	writeJSON(w, http.StatusOK, records)
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// handleRun 处理调度请求
+// 检查任务是否注册、是否暂停，占用并发槽位并提取请求头中的追踪上下文后交给 SDK 执行；请求格式错误时由 SDK 返回错误
+func (e *executorImpl) handleRun(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRunRequestSize))
+	_ = r.Body.Close()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var req xxl.RunReq
+	held := false
+	if err := json.Unmarshal(body, &req); err == nil {
+		// 任务未注册或已注销
+		if _, ok := e.registry.Get(req.ExecutorHandler); !ok {
+			log.Warn("XXL-JOB run request for unknown task handler",
+				zap.String("task_name", req.ExecutorHandler),
+				zap.Int64("log_id", req.LogID),
+			)
+			writeReturn(w, xxl.FailureCode, handlerNotFoundMsg(req.ExecutorHandler))
+			return
+		}
+		run := taskRun{
+			taskName:   req.ExecutorHandler,
+			logID:      req.LogID,
+			jobID:      req.JobID,
+			shardIndex: req.BroadcastIndex,
+			shardTotal: req.BroadcastTotal,
+		}
+
+		// 本地暂停的任务直接返回失败（SDK 的执行回调总是返回成功码，不能交给 SDK 执行）
+		if paused, ok := e.pauses.get(req.ExecutorHandler); ok {
+			e.rejectPausedRequest(req.ExecutorHandler, req.LogID, paused)
+			msg := pausedMessage(paused)
+			e.history.add(newExecutionRecord(run, time.Now(), ExecutionPaused, msg))
+			writeReturn(w, xxl.FailureCode, msg)
+			return
+		}
+		e.limits.rememberJob(req.JobID, req.ExecutorHandler)
+
+		// 应答调度中心之前占用并发槽位（wait 模式在这里排队），达到上限时返回失败，便于调度中心故障转移；
+		// 占用的槽位交给 runTask 使用，避免检查之后、执行之前被其他调度抢占
+		if req.LogID > 0 {
+			release, limitErr := e.limits.acquire(r.Context(), req.ExecutorHandler)
+			if limitErr != nil {
+				log.Warn("XXL-JOB run request rejected by concurrency limit",
+					zap.String("task_name", req.ExecutorHandler),
+					zap.Int64("log_id", req.LogID),
+					zap.Error(limitErr),
+				)
+				msg := fmt.Sprintf("executor is busy: %v", limitErr)
+				e.history.add(newExecutionRecord(run, time.Now(), ExecutionRejected, msg))
+				writeReturn(w, xxl.FailureCode, msg)
+				return
+			}
+			e.limits.hold(req.LogID, release)
+			held = true
+		}
+
+		if carrier := traceCarrierFromHeader(r.Header); len(carrier) > 0 && req.LogID > 0 {
+			e.traceCarriers.Put(req.LogID, carrier)
+		}
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if !held {
+		e.executor.RunTask(w, r)
+		return
+	}
+
+	// SDK 拒绝调度（例如单机串行策略下任务仍在运行）时不会执行任务，释放已占用的槽位；
+	// SDK 只有接受调度时返回 {"code":200}，拒绝时返回回调格式的数组
+	resp := &bufferedResponse{ResponseWriter: w}
+	e.executor.RunTask(resp, r)
+	var ret struct {
+		Code int64 `json:"code"`
+	}
+	if err := json.Unmarshal(resp.body.Bytes(), &ret); err != nil || ret.Code != xxl.SuccessCode {
+		e.limits.drop(req.LogID)
+	}
+	_, _ = w.Write(resp.body.Bytes())
+}
+
+// bufferedResponse 缓存响应内容，便于检查 SDK 的处理结果后再写出
+type bufferedResponse struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write 写入缓存
+func (r *bufferedResponse) Write(p []byte) (int, error) {
+	return r.body.Write(p)
+}
+
+// handleIdleBeat 处理忙碌检测
+// 任务达到并发上限时返回忙碌，使调度中心的忙碌转移（BUSYOVER）路由生效；否则交给 SDK 检查任务是否在运行
+func (e *executorImpl) handleIdleBeat(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRunRequestSize))
+	_ = r.Body.Close()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		JobID int64 `json:"jobId"`
+	}
+	if err := json.Unmarshal(body, &req); err == nil && e.limits.jobBusy(req.JobID) {
+		writeReturn(w, xxl.FailureCode, "Task is busy")
+		return
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	e.executor.IdleBeat(w, r)
+}
+
+// writeReturn 写入调度中心协议的通用返回结果
+func writeReturn(w http.ResponseWriter, code int64, msg string) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"code": code,
+		"msg":  msg,
+	})
+	_, _ = w.Write(data)
+}
+
+// serve 启动 HTTP 服务并阻塞，直到收到退出信号、调用 Stop 或服务异常退出
+func (e *executorImpl) serve(stopCh <-chan struct{}) error {
+	server := &http.Server{
+		Addr:         ":" + e.options().executorPort,
+		Handler:      e.newServeMux(),
+		WriteTimeout: serverWriteTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGQUIT, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	var serveErr error
+	select {
+	case sig := <-quit:
+		log.Info("XXL-JOB executor received signal, shutting down", zap.String("signal", sig.String()))
+		// 从调度中心摘除执行器
+		e.executor.Stop()
+	case <-stopCh:
+	case err := <-errCh:
+		serveErr = fmt.Errorf("executor server failed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Warn("Failed to shutdown XXL-JOB executor server", zap.Error(err))
+	}
+	return serveErr
+}
@@ -37,30 +37,70 @@ func NewTaskRegistry() *TaskRegistry {
 }
 
 // Register 注册任务
-func (r *TaskRegistry) Register(name string, handler TaskHandler) error {
-	if name == "" {
+func (r *TaskRegistry) Register(name string, handler TaskHandler, opts ...TaskOption) error {
+	return r.Add(newTaskInfo(name, handler, opts...))
+}
+
+// Add 注册任务信息
+func (r *TaskRegistry) Add(info *TaskInfo) error {
+	if info == nil || info.Name == "" {
 		return fmt.Errorf("task name cannot be empty")
 	}
-	if handler == nil {
+	if info.Handler == nil {
 		return fmt.Errorf("task handler cannot be nil")
 	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.tasks[name]; exists {
-		return fmt.Errorf("task %s already registered", name)
+	if _, exists := r.tasks[info.Name]; exists {
+		return fmt.Errorf("task %s already registered", info.Name)
 	}
 
-	r.tasks[name] = &TaskInfo{
-		Name:         name,
-		Handler:      handler,
-		RegisteredAt: time.Now(),
+	if info.RegisteredAt.IsZero() {
+		info.RegisteredAt = time.Now()
 	}
+	r.tasks[info.Name] = info
 
 	return nil
 }
 
+// Replace 替换已注册的任务信息
+func (r *TaskRegistry) Replace(info *TaskInfo) error {
+	if info == nil || info.Name == "" {
+		return fmt.Errorf("task name cannot be empty")
+	}
+	if info.Handler == nil {
+		return fmt.Errorf("task handler cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tasks[info.Name]; !exists {
+		return fmt.Errorf("task %s not found", info.Name)
+	}
+
+	if info.RegisteredAt.IsZero() {
+		info.RegisteredAt = time.Now()
+	}
+	r.tasks[info.Name] = info
+
+	return nil
+}
+
+// newTaskInfo 创建任务信息并应用注册选项
+func newTaskInfo(name string, handler TaskHandler, opts ...TaskOption) *TaskInfo {
+	info := &TaskInfo{
+		Name:    name,
+		Handler: handler,
+	}
+	for _, opt := range opts {
+		opt(info)
+	}
+	return info
+}
+
 // Get 获取任务信息
 func (r *TaskRegistry) Get(name string) (*TaskInfo, bool) {
 	r.mu.RLock()
@@ -103,7 +143,7 @@ func (r *TaskRegistry) Count() int {
 	return len(r.tasks)
 }
 
-// Unregister 注销任务（通常不需要，但提供此方法以支持动态管理）
+// Unregister 注销任务
 func (r *TaskRegistry) Unregister(name string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -0,0 +1,83 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import "time"
+
+// TaskOption 任务注册选项
+type TaskOption func(*TaskInfo)
+
+// TaskDescription 设置任务描述
+func TaskDescription(description string) TaskOption {
+	return func(t *TaskInfo) {
+		t.Description = description
+	}
+}
+
+// TaskTags 为任务添加标签
+func TaskTags(tags ...string) TaskOption {
+	return func(t *TaskInfo) {
+		t.Tags = append(t.Tags, tags...)
+	}
+}
+
+// TaskTimeout 设置任务超时时间，作用于整次执行（包括任务级中间件中的重试）
+func TaskTimeout(timeout time.Duration) TaskOption {
+	return func(t *TaskInfo) {
+		t.Timeout = timeout
+	}
+}
+
+// TaskConcurrencyLimit 设置任务并发限制，覆盖配置中的 TaskConcurrency；零值表示不限制
+func TaskConcurrencyLimit(cfg ConcurrencyConfig) TaskOption {
+	return func(t *TaskInfo) {
+		t.Concurrency = cfg
+		t.concurrencySet = true
+	}
+}
+
+// TaskMiddlewares 添加任务级中间件，按添加顺序由外到内执行，位于全局中间件之内
+func TaskMiddlewares(middlewares ...TaskMiddleware) TaskOption {
+	return func(t *TaskInfo) {
+		t.Middlewares = append(t.Middlewares, middlewares...)
+	}
+}
+
+// HasTag 判断任务是否带有指定标签
+func (t *TaskInfo) HasTag(tag string) bool {
+	for _, v := range t.Tags {
+		if v == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// buildHandler 按固定顺序组装任务处理器：
+// 全局中间件（外层）→ 任务超时 → 任务级中间件 → 任务处理器
+// 组装时根据任务信息决定 When 条件中间件是否启用，并将生效的中间件链记录到 EffectiveMiddlewares
+func (t *TaskInfo) buildHandler(global []TaskMiddleware) TaskHandler {
+	handler, taskNames := buildChain(t, t.Handler, t.Middlewares)
+	if t.Timeout > 0 {
+		handler = TimeoutMiddleware(t.Timeout)(handler)
+		taskNames = append([]string{"timeout"}, taskNames...)
+	}
+	handler, globalNames := buildChain(t, handler, global)
+
+	t.EffectiveMiddlewares = append(globalNames, taskNames...)
+	return handler
+}
@@ -18,6 +18,7 @@ package xxljob
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -31,40 +32,76 @@ import (
 	"go.uber.org/zap"
 )
 
+// taskRun 单次任务执行的调度信息
+type taskRun struct {
+	taskName   string
+	param      string
+	logID      int64
+	jobID      int64
+	shardIndex int64
+	shardTotal int64
+}
+
+// spanAttributes 按追踪配置生成任务 span 属性
+func (r taskRun) spanAttributes(tracing TaskTraceConfig, logParam string, redactor *Redactor) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("xxljob.task.name", r.taskName),
+		attribute.Int64("xxljob.log.id", r.logID),
+	}
+	if tracing.includeAttribute(TraceAttrParam) {
+		attrs = append(attrs, attribute.String("xxljob.task.param", redactor.TruncateAttribute(logParam)))
+	}
+	if tracing.includeAttribute(TraceAttrJobID) && r.jobID > 0 {
+		attrs = append(attrs, attribute.Int64("xxljob.job.id", r.jobID))
+	}
+	if tracing.includeAttribute(TraceAttrShard) && r.shardTotal > 0 {
+		attrs = append(attrs,
+			attribute.Int64("xxljob.shard.index", r.shardIndex),
+			attribute.Int64("xxljob.shard.total", r.shardTotal),
+		)
+	}
+	return attrs
+}
+
 // executeTaskWithTrace 带追踪的任务执行包装器
 // 统一处理日志、追踪、Metrics 等横切关注点
 func executeTaskWithTrace(
 	ctx context.Context,
-	taskName string,
-	param string,
-	logID int64,
+	run taskRun,
 	handler TaskHandler,
-	enableTrace bool,
+	tracing TaskTraceConfig,
+	redactor *Redactor,
+	logEvents TraceLogEventsConfig,
+	spanOpts ...trace.SpanStartOption,
 ) (result string, err error) {
+	taskName, param, logID := run.taskName, run.param, run.logID
 	startTime := time.Now()
 
-	// 创建追踪 span
+	// 参数脱敏后再写入日志和追踪属性，处理器收到的仍是原始参数
+	logParam := redactor.Redact(taskName, param)
+
+	// 创建追踪 span（上游调用链已采样时跟随上游，否则按任务采样比例）
+	spanOpts = append(spanOpts, trace.WithAttributes(run.spanAttributes(tracing, logParam, redactor)...))
+	sampled := tracing.shouldSample(trace.SpanContextFromContext(ctx).IsSampled())
 	var span trace.Span
-	if enableTrace {
-		ctx, span = pkgtrace.StartSpan(ctx, "xxljob.task.execute",
-			trace.WithAttributes(
-				attribute.String("xxljob.task.name", taskName),
-				attribute.String("xxljob.task.param", param),
-				attribute.Int64("xxljob.log.id", logID),
-			),
-		)
+	if sampled {
+		ctx, span = pkgtrace.StartSpan(ctx, "xxljob.task.execute", spanOpts...)
 		defer span.End()
 	}
 
 	// 记录任务开始日志（同时写入文件日志，如果 LogWriter 存在）
-	logWriter := LogWriterFromContext(ctx)
+	logWriter := JobLogFromContext(ctx)
+	if sampled {
+		// 执行期间的任务日志行同时记录为 span 事件
+		defer attachSpanEvents(logWriter, newSpanLogEvents(span, logEvents, redactor))()
+	}
 	if logWriter != nil {
-		logWriter.Write("XXL-JOB task [%s] started, param: %s", taskName, param)
+		logWriter.Info(fmt.Sprintf("XXL-JOB task [%s] started", taskName), "param", logParam)
 	}
 
 	log.FromContext(ctx).Info("XXL-JOB task started",
 		zap.String("task_name", taskName),
-		zap.String("param", param),
+		zap.String("param", logParam),
 		zap.Int64("log_id", logID),
 	)
 
@@ -75,7 +112,9 @@ func executeTaskWithTrace(
 	// 记录 Metrics
 	if metrics.IsEnabled() {
 		status := "success"
-		if err != nil {
+		if errors.Is(err, ErrCircuitOpen) {
+			status = "circuit_open"
+		} else if err != nil {
 			status = "error"
 		}
 		metrics.XXLJobTaskTotal.WithLabelValues(taskName, status).Inc()
@@ -86,43 +125,52 @@ func executeTaskWithTrace(
 	if err != nil {
 		// 记录错误日志（同时写入文件日志）
 		if logWriter != nil {
-			logWriter.Write("XXL-JOB task [%s] failed after %v: %v", taskName, duration, err)
+			logWriter.Error(fmt.Sprintf("XXL-JOB task [%s] failed", taskName),
+				"duration", duration,
+				"error", err,
+			)
 		}
 
 		log.FromContext(ctx).Error("XXL-JOB task failed",
 			zap.String("task_name", taskName),
-			zap.String("param", param),
+			zap.String("param", logParam),
 			zap.Int64("log_id", logID),
 			zap.Duration("duration", duration),
 			zap.Error(err),
 		)
 
-		// 更新追踪状态
-		if enableTrace && span != nil {
+		// 更新追踪状态，未采样时按配置补记 span
+		if span != nil {
 			span.SetStatus(codes.Error, err.Error())
 			span.RecordError(err)
 			span.SetAttributes(
 				attribute.String("xxljob.task.status", "failed"),
 				attribute.String("xxljob.task.error", err.Error()),
 			)
+		} else if !tracing.Disabled && tracing.AlwaysSampleErrors {
+			recordErrorSpan(ctx, err, startTime, spanOpts)
 		}
 
 		result = fmt.Sprintf("FAIL: %v", err)
+		if errors.Is(err, ErrCircuitOpen) {
+			// 熔断时使用独立的结果前缀，便于在调度中心区分
+			result = fmt.Sprintf("CIRCUIT_OPEN: %v", err)
+		}
 	} else {
 		// 记录成功日志（同时写入文件日志）
 		if logWriter != nil {
-			logWriter.Write("XXL-JOB task [%s] completed successfully in %v", taskName, duration)
+			logWriter.Info(fmt.Sprintf("XXL-JOB task [%s] completed successfully", taskName), "duration", duration)
 		}
 
 		log.FromContext(ctx).Info("XXL-JOB task completed",
 			zap.String("task_name", taskName),
-			zap.String("param", param),
+			zap.String("param", logParam),
 			zap.Int64("log_id", logID),
 			zap.Duration("duration", duration),
 		)
 
 		// 更新追踪状态
-		if enableTrace && span != nil {
+		if span != nil {
 			span.SetStatus(codes.Ok, "")
 			span.SetAttributes(
 				attribute.String("xxljob.task.status", "success"),
@@ -135,3 +183,19 @@ func executeTaskWithTrace(
 
 	return result, nil
 }
+
+// recordErrorSpan 为未采样但执行失败的任务补记 span，起止时间与实际执行一致
+func recordErrorSpan(ctx context.Context, err error, startTime time.Time, spanOpts []trace.SpanStartOption) {
+	opts := append(spanOpts,
+		trace.WithTimestamp(startTime),
+		trace.WithAttributes(attribute.Bool("xxljob.trace.sampled_on_error", true)),
+	)
+	_, span := pkgtrace.StartSpan(ctx, "xxljob.task.execute", opts...)
+	span.SetStatus(codes.Error, err.Error())
+	span.RecordError(err)
+	span.SetAttributes(
+		attribute.String("xxljob.task.status", "failed"),
+		attribute.String("xxljob.task.error", err.Error()),
+	)
+	span.End()
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultTraceLogEventLimit 单个任务 span 默认最多记录的日志事件数
+const defaultTraceLogEventLimit = 100
+
+// TraceLogEventsConfig 任务日志行记录为 span 事件的配置
+// 零值即启用：每次执行最多记录 Limit 条事件，INFO 及以下级别按 SampleRate 采样，
+// WARN、ERROR 级别不参与采样（仍受 Limit 限制）
+type TraceLogEventsConfig struct {
+	Disabled   bool    `yaml:"disabled" env:"XXL_JOB_TRACE_LOG_EVENTS_DISABLED" default:"false"`
+	Limit      int     `yaml:"limit" env:"XXL_JOB_TRACE_LOG_EVENTS_LIMIT" default:"100"`
+	SampleRate float64 `yaml:"sample_rate" env:"XXL_JOB_TRACE_LOG_EVENTS_SAMPLE_RATE" default:"1"` // (0, 1]，0 表示使用默认值 1
+}
+
+// DefaultTraceLogEventsConfig 返回默认日志事件配置
+func DefaultTraceLogEventsConfig() TraceLogEventsConfig {
+	return TraceLogEventsConfig{
+		Limit:      defaultTraceLogEventLimit,
+		SampleRate: 1,
+	}
+}
+
+// Validate 验证日志事件配置
+func (c TraceLogEventsConfig) Validate() error {
+	if c.Limit < 0 {
+		return fmt.Errorf("limit must be >= 0")
+	}
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return fmt.Errorf("sample_rate must be between 0 and 1")
+	}
+	return nil
+}
+
+// spanLogEvents 将任务日志行记录为 span 事件
+type spanLogEvents struct {
+	span     trace.Span
+	limit    int
+	rate     float64
+	redactor *Redactor // 用于截断过长的事件属性
+
+	mu       sync.Mutex
+	recorded int
+	dropped  int
+}
+
+// newSpanLogEvents 创建日志事件记录器，span 未采样或配置禁用时返回 nil
+func newSpanLogEvents(span trace.Span, cfg TraceLogEventsConfig, redactor *Redactor) *spanLogEvents {
+	if cfg.Disabled || span == nil || !span.IsRecording() {
+		return nil
+	}
+	limit := cfg.Limit
+	if limit <= 0 {
+		limit = defaultTraceLogEventLimit
+	}
+	rate := cfg.SampleRate
+	if rate <= 0 || rate > 1 {
+		rate = 1
+	}
+	return &spanLogEvents{
+		span:     span,
+		limit:    limit,
+		rate:     rate,
+		redactor: redactor,
+	}
+}
+
+// record 记录一行日志
+func (e *spanLogEvents) record(level LogLevel, msg string, keysAndValues []interface{}) {
+	if level < LogLevelWarn && e.rate < 1 && rand.Float64() >= e.rate {
+		return
+	}
+
+	e.mu.Lock()
+	if e.recorded >= e.limit {
+		e.dropped++
+		e.mu.Unlock()
+		return
+	}
+	e.recorded++
+	e.mu.Unlock()
+
+	fields := normalizeLogFields(keysAndValues)
+	attrs := make([]attribute.KeyValue, 0, len(fields)+2)
+	attrs = append(attrs,
+		attribute.String("log.severity", level.String()),
+		attribute.String("log.message", e.redactor.TruncateAttribute(msg)),
+	)
+	for _, f := range fields {
+		attrs = append(attrs, attribute.String("log.field."+f.key, e.redactor.TruncateAttribute(fmt.Sprint(f.value))))
+	}
+	e.span.AddEvent("log", trace.WithAttributes(attrs...))
+}
+
+// finish 在 span 上记录事件统计
+func (e *spanLogEvents) finish() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.span.SetAttributes(attribute.Int("xxljob.log.events", e.recorded))
+	if e.dropped > 0 {
+		e.span.SetAttributes(attribute.Int("xxljob.log.events_dropped", e.dropped))
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans 将全局 TracerProvider 替换为记录 span 的实现，测试结束后恢复
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+// spanAttribute 查找 span 属性
+func spanAttribute(attrs []attribute.KeyValue, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestSpanLogEvents(t *testing.T) {
+	recorder := recordSpans(t)
+
+	store := NewMemoryLogStore(10, 100)
+	w, err := newLogWriter(store, 1, logWriterOptions{syncPolicy: SyncEveryLine})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	_, span := otel.Tracer("test").Start(context.Background(), "task")
+	events := newSpanLogEvents(span, TraceLogEventsConfig{Limit: 2}, nil)
+	detach := attachSpanEvents(&JobLog{w: w}, events)
+
+	jobLog := &JobLog{w: w}
+	jobLog.Info("first", "attempt", 1)
+	jobLog.Warn("second")
+	jobLog.Error("third")
+	detach()
+	jobLog.Info("after detach")
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended %d spans, want 1", len(spans))
+	}
+	got := spans[0]
+	if len(got.Events()) != 2 {
+		t.Fatalf("recorded %d events, want 2", len(got.Events()))
+	}
+	first := got.Events()[0]
+	if v, _ := spanAttribute(first.Attributes, "log.message"); v.AsString() != "first" {
+		t.Errorf("log.message = %q, want first", v.AsString())
+	}
+	if v, _ := spanAttribute(first.Attributes, "log.field.attempt"); v.AsString() != "1" {
+		t.Errorf("log.field.attempt = %q, want 1", v.AsString())
+	}
+	if v, _ := spanAttribute(got.Events()[1].Attributes, "log.severity"); v.AsString() != LogLevelWarn.String() {
+		t.Errorf("log.severity = %q, want %s", v.AsString(), LogLevelWarn)
+	}
+	if v, _ := spanAttribute(got.Attributes(), "xxljob.log.events"); v.AsInt64() != 2 {
+		t.Errorf("xxljob.log.events = %d, want 2", v.AsInt64())
+	}
+	if v, _ := spanAttribute(got.Attributes(), "xxljob.log.events_dropped"); v.AsInt64() != 1 {
+		t.Errorf("xxljob.log.events_dropped = %d, want 1", v.AsInt64())
+	}
+}
+
+func TestRetryAttemptSpans(t *testing.T) {
+	recorder := recordSpans(t)
+
+	calls := 0
+	handler := RetryPolicyMiddleware(RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond})(
+		func(ctx context.Context, param string) error {
+			calls++
+			if calls < 3 {
+				return errors.New("temporary")
+			}
+			return nil
+		})
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "task")
+	if err := handler(ctx, ""); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	parent.End()
+
+	var attempts []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "xxljob.task.attempt" {
+			attempts = append(attempts, span)
+		}
+	}
+	if len(attempts) != 3 {
+		t.Fatalf("recorded %d attempt spans, want 3", len(attempts))
+	}
+	for i, span := range attempts {
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("attempt %d parent = %s, want task span", i+1, span.Parent().SpanID())
+		}
+		if v, _ := spanAttribute(span.Attributes(), "xxljob.retry.attempt"); v.AsInt64() != int64(i+1) {
+			t.Errorf("attempt %d xxljob.retry.attempt = %d", i+1, v.AsInt64())
+		}
+		_, hasBackoff := spanAttribute(span.Attributes(), "xxljob.retry.backoff_ms")
+		if !hasBackoff {
+			t.Errorf("attempt %d has no xxljob.retry.backoff_ms", i+1)
+		}
+	}
+	if v, _ := spanAttribute(recorder.Ended()[len(recorder.Ended())-1].Attributes(), "xxljob.retry.attempts"); v.AsInt64() != 3 {
+		t.Errorf("xxljob.retry.attempts = %d, want 3", v.AsInt64())
+	}
+}
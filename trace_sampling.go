@@ -0,0 +1,82 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package xxljob
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// 可配置的任务 span 属性
+const (
+	TraceAttrParam = "param"  // 任务参数（脱敏后）
+	TraceAttrShard = "shard"  // 分片序号和分片总数
+	TraceAttrJobID = "job_id" // 调度中心任务 ID
+	TraceAttrNone  = "none"   // 不记录可选属性
+)
+
+// TaskTraceConfig 单个任务的追踪配置
+// 未配置的任务根据 EnableTrace 决定是否追踪，并全量采样、记录全部属性
+type TaskTraceConfig struct {
+	Disabled bool `yaml:"disabled"`
+	// SampleRatio 采样比例 (0, 1]，0 表示使用默认值 1；上游已采样的调用链总是采样
+	SampleRatio float64 `yaml:"sample_ratio"`
+	// AlwaysSampleErrors 未被采样的执行失败时仍补记一个 span（不包含子 span 和日志事件）
+	AlwaysSampleErrors bool `yaml:"always_sample_errors"`
+	// Attributes 记录的可选属性：param、shard、job_id；为空时记录全部，none 表示都不记录
+	Attributes []string `yaml:"attributes"`
+}
+
+// Validate 验证任务追踪配置
+func (c TaskTraceConfig) Validate() error {
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("sample_ratio must be between 0 and 1")
+	}
+	for _, attr := range c.Attributes {
+		switch strings.ToLower(attr) {
+		case TraceAttrParam, TraceAttrShard, TraceAttrJobID, TraceAttrNone:
+		default:
+			return fmt.Errorf("unknown trace attribute: %s", attr)
+		}
+	}
+	return nil
+}
+
+// shouldSample 决定本次执行是否采样
+func (c TaskTraceConfig) shouldSample(parentSampled bool) bool {
+	if c.Disabled {
+		return false
+	}
+	if parentSampled || c.SampleRatio <= 0 || c.SampleRatio >= 1 {
+		return true
+	}
+	return rand.Float64() < c.SampleRatio
+}
+
+// includeAttribute 判断是否记录指定的可选属性
+func (c TaskTraceConfig) includeAttribute(name string) bool {
+	if len(c.Attributes) == 0 {
+		return true
+	}
+	for _, attr := range c.Attributes {
+		if strings.EqualFold(attr, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -27,7 +27,34 @@ type Executor interface {
 	// RegTask 注册任务
 	// taskName: 任务名称，必须与 XXL-JOB 管理端配置的 JobHandler 一致
 	// handler: 任务处理函数
-	RegTask(taskName string, handler TaskHandler) error
+	// opts: 任务选项（描述、标签、超时、并发限制、任务级中间件）
+	// 执行器运行期间也可以注册
+	RegTask(taskName string, handler TaskHandler, opts ...TaskOption) error
+
+	// ReplaceTask 替换已注册任务的处理器和选项
+	// 正在执行的任务继续使用原处理器完成，之后的调度使用新处理器
+	ReplaceTask(taskName string, handler TaskHandler, opts ...TaskOption) error
+
+	// UnregisterTask 注销任务
+	// 正在执行的任务继续执行完成，之后的调度返回 handler not found
+	UnregisterTask(taskName string) error
+
+	// PauseTask 在本执行器上暂停任务，不影响调度中心和其他执行器
+	// 暂停期间的调度返回失败结果（携带 reason）并写入任务日志；配置了 StateFile 时重启后保留
+	PauseTask(taskName, reason string) error
+
+	// ResumeTask 恢复暂停的任务
+	ResumeTask(taskName string) error
+
+	// PausedTasks 返回本执行器上暂停的任务
+	PausedTasks() map[string]PausedTask
+
+	// ExecutionHistory 查询本执行器的执行历史，taskName 为空时查询所有任务，按开始时间倒序返回
+	ExecutionHistory(taskName string, filter HistoryFilter) []ExecutionRecord
+
+	// ApplyConfig 在运行期间应用配置，日志、追踪、静默模式、脱敏等配置立即生效，
+	// 需要重启才能生效的配置项在结果中返回
+	ApplyConfig(cfg *Config) (*ConfigApplyResult, error)
 
 	// Run 启动执行器（阻塞调用）
 	// 通常在单独的 goroutine 中调用
@@ -41,6 +68,9 @@ type Executor interface {
 
 	// GetTaskNames 获取所有已注册的任务名称
 	GetTaskNames() []string
+
+	// GetTaskInfo 获取已注册任务的信息（包括注册选项）
+	GetTaskInfo(taskName string) (*TaskInfo, bool)
 }
 
 // TaskHandler 任务处理器函数类型
@@ -51,9 +81,19 @@ type TaskHandler func(ctx context.Context, param string) error
 
 // TaskInfo 任务信息
 type TaskInfo struct {
-	Name         string      // 任务名称
-	Handler      TaskHandler // 任务处理器
-	RegisteredAt time.Time   // 注册时间
+	Name         string            // 任务名称
+	Handler      TaskHandler       // 任务处理器（注册到执行器后为组装了中间件的处理器）
+	RegisteredAt time.Time         // 注册时间
+	Description  string            // 任务描述
+	Tags         []string          // 任务标签
+	Timeout      time.Duration     // 任务超时时间，0 表示不限制
+	Concurrency  ConcurrencyConfig // 任务并发限制，未通过 TaskConcurrencyLimit 设置时使用配置中的 TaskConcurrency
+	Middlewares  []TaskMiddleware  // 任务级中间件
+
+	// EffectiveMiddlewares 注册时生效的中间件链（由外到内），未命名的中间件记为 anonymous
+	EffectiveMiddlewares []string
+
+	concurrencySet bool // 是否通过 TaskConcurrencyLimit 设置了并发限制
 }
 
 // Middleware 中间件函数类型
@@ -62,6 +102,7 @@ type Middleware func(next TaskHandler) TaskHandler
 
 // LogWriter 日志写入器接口
 // 任务执行过程中可以使用此接口写入多行日志
+// 需要分级日志或 io.Writer 时使用 JobLogFromContext 获取 *JobLog
 type LogWriter interface {
 	// Write 写入一行日志（自动添加时间戳）
 	Write(format string, args ...interface{})
@@ -72,8 +113,13 @@ type LogWriter interface {
 
 // HealthStatus 健康状态
 type HealthStatus struct {
-	Running   bool      // 是否正在运行
-	TaskCount int       // 已注册任务数量
-	StartedAt time.Time // 启动时间
-	LastError error     // 最后一次错误
+	Running         bool                  // 是否正在运行
+	TaskCount       int                   // 已注册任务数量
+	StartedAt       time.Time             // 启动时间
+	LastError       error                 // 最后一次错误
+	RunningTasks    map[string]int        // 各任务正在执行的数量
+	CircuitBreakers []CircuitBreakerState // 熔断器状态
+	PausedTasks     map[string]PausedTask // 本执行器上暂停的任务
+	RegisteredAt    time.Time             // 最近一次向调度中心注册成功的时间
+	FallbackActive  bool                  // 本地兜底调度是否正在生效
 }
@@ -16,6 +16,8 @@
 
 package xxljob
 
+import "time"
+
 // NewExecutorBuilder 创建执行器构建器
 // 示例：
 //
@@ -73,12 +75,66 @@ func (b *ExecutorBuilder) LogPath(path string) *ExecutorBuilder {
 	return b
 }
 
+// StateFile 设置本地状态文件（保存暂停的任务，重启后恢复）
+func (b *ExecutorBuilder) StateFile(path string) *ExecutorBuilder {
+	b.builder.StateFile(path)
+	return b
+}
+
 // LogRetentionDays 设置日志保留天数
 func (b *ExecutorBuilder) LogRetentionDays(days int) *ExecutorBuilder {
 	b.builder.LogRetentionDays(days)
 	return b
 }
 
+// LogSyncPolicy 设置日志落盘策略
+func (b *ExecutorBuilder) LogSyncPolicy(policy LogSyncPolicy) *ExecutorBuilder {
+	b.builder.LogSyncPolicy(policy)
+	return b
+}
+
+// LogFlushInterval 设置日志缓冲刷新间隔
+func (b *ExecutorBuilder) LogFlushInterval(interval time.Duration) *ExecutorBuilder {
+	b.builder.LogFlushInterval(interval)
+	return b
+}
+
+// LogBufferSize 设置日志缓冲区大小（字节）
+func (b *ExecutorBuilder) LogBufferSize(size int) *ExecutorBuilder {
+	b.builder.LogBufferSize(size)
+	return b
+}
+
+// LogFormat 设置任务日志行格式
+func (b *ExecutorBuilder) LogFormat(format LogFormat) *ExecutorBuilder {
+	b.builder.LogFormat(format)
+	return b
+}
+
+// LogLevel 设置任务日志最低级别
+func (b *ExecutorBuilder) LogLevel(level LogLevel) *ExecutorBuilder {
+	b.builder.LogLevel(level)
+	return b
+}
+
+// TaskLogLevel 为指定任务设置最低日志级别
+func (b *ExecutorBuilder) TaskLogLevel(taskName string, level LogLevel) *ExecutorBuilder {
+	b.builder.TaskLogLevel(taskName, level)
+	return b
+}
+
+// LogStore 设置日志存储后端（默认根据 LogPath 选择文件或内存存储）
+func (b *ExecutorBuilder) LogStore(store LogStore) *ExecutorBuilder {
+	b.builder.LogStore(store)
+	return b
+}
+
+// Redactor 设置参数脱敏器（默认启用内置规则），传入 nil 表示关闭脱敏
+func (b *ExecutorBuilder) Redactor(redactor *Redactor) *ExecutorBuilder {
+	b.builder.Redactor(redactor)
+	return b
+}
+
 // Trace 启用/禁用追踪
 func (b *ExecutorBuilder) Trace(enabled bool) *ExecutorBuilder {
 	b.builder.Trace(enabled)
@@ -86,17 +142,65 @@ func (b *ExecutorBuilder) Trace(enabled bool) *ExecutorBuilder {
 }
 
 // Middleware 添加中间件
-func (b *ExecutorBuilder) Middleware(middleware Middleware) *ExecutorBuilder {
+func (b *ExecutorBuilder) Middleware(middleware TaskMiddleware) *ExecutorBuilder {
 	b.builder.Middleware(middleware)
 	return b
 }
 
 // Middlewares 批量添加中间件
-func (b *ExecutorBuilder) Middlewares(middlewares ...Middleware) *ExecutorBuilder {
+func (b *ExecutorBuilder) Middlewares(middlewares ...TaskMiddleware) *ExecutorBuilder {
 	b.builder.Middlewares(middlewares...)
 	return b
 }
 
+// TracePropagation 设置上游追踪上下文关联方式（默认作为父 span）
+func (b *ExecutorBuilder) TracePropagation(mode TracePropagationMode) *ExecutorBuilder {
+	b.builder.TracePropagation(mode)
+	return b
+}
+
+// TaskTrace 为指定任务设置追踪配置（采样比例、失败补记、记录的属性），覆盖 EnableTrace
+func (b *ExecutorBuilder) TaskTrace(taskName string, cfg TaskTraceConfig) *ExecutorBuilder {
+	b.builder.TaskTrace(taskName, cfg)
+	return b
+}
+
+// TraceLogEvents 设置任务日志行记录为 span 事件的方式（默认每次执行最多 100 条）
+func (b *ExecutorBuilder) TraceLogEvents(cfg TraceLogEventsConfig) *ExecutorBuilder {
+	b.builder.TraceLogEvents(cfg)
+	return b
+}
+
+// History 设置执行历史（默认每个任务在内存中保留最近 100 条记录）
+func (b *ExecutorBuilder) History(cfg HistoryConfig) *ExecutorBuilder {
+	b.builder.History(cfg)
+	return b
+}
+
+// Fallback 设置调度中心不可达时的本地兜底调度（默认关闭）
+func (b *ExecutorBuilder) Fallback(cfg FallbackConfig) *ExecutorBuilder {
+	b.builder.Fallback(cfg)
+	return b
+}
+
+// FallbackLocker 设置兜底调度使用的分布式锁，多副本部署时避免重复执行
+func (b *ExecutorBuilder) FallbackLocker(locker Locker) *ExecutorBuilder {
+	b.builder.FallbackLocker(locker)
+	return b
+}
+
+// Concurrency 设置执行器级并发限制（默认不限制）
+func (b *ExecutorBuilder) Concurrency(cfg ConcurrencyConfig) *ExecutorBuilder {
+	b.builder.Concurrency(cfg)
+	return b
+}
+
+// TaskConcurrency 为指定任务设置并发限制
+func (b *ExecutorBuilder) TaskConcurrency(taskName string, cfg ConcurrencyConfig) *ExecutorBuilder {
+	b.builder.TaskConcurrency(taskName, cfg)
+	return b
+}
+
 // QuietMode 启用/禁用静默模式（不输出心跳/注册日志）
 func (b *ExecutorBuilder) QuietMode(enabled bool) *ExecutorBuilder {
 	b.builder.QuietMode(enabled)